@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v32/github"
 	"github.com/spf13/cobra"
@@ -103,26 +104,38 @@ func addActionsListCmd(root *cobra.Command) {
 
 func addActionsRunCmd(root *cobra.Command) {
 	var (
-		tokenPath  string
-		query      string
-		ref        string
-		inputs     string
-		workflowID int64
+		tokenPath       string
+		query           string
+		ref             string
+		inputs          string
+		workflowID      int64
+		watch           bool
+		timeout         time.Duration
+		pollInterval    time.Duration
+		maxPollInterval time.Duration
 		// TODO: interactive inputs based on workflow file config.
 	)
 
 	var cmd = &cobra.Command{
-		Use:   "run org/repo --query OneResult",
+		Use:   "run org/repo [org/repo...] --query OneResult",
 		Short: "Run a GitHub Actions workflow for a given repository.",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			or := strings.Split(args[0], "/")
-			if len(or) != 2 {
-				return fmt.Errorf("unexpected format %q, expected %q", args[0], "org/repo")
+		Long: `
+Run a GitHub Actions workflow for the given repositories, the workflow is
+selected by --query in each repository, or by --id for a single repository.
+
+With --watch, the command waits for the runs it created to complete, printing
+the status of their jobs and steps as they change. It exits with code 1 if a run
+did not succeed or did not complete within --timeout.
+`,
+		Args: cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validation
+			if workflowID != 0 && len(args) > 1 {
+				return errors.New("--id can only be used with a single repository, use --query instead")
 			}
-			org := or[0]
-			repo := or[1]
-
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
 			gh, err := ghutil.NewGithubClient(tokenPath)
 			if err != nil {
 				return err
@@ -135,35 +148,47 @@ func addActionsRunCmd(root *cobra.Command) {
 				}
 			}
 
-			if workflowID == 0 {
-				workflows, err := gh.ListWorkflows(org, repo)
-				if err != nil {
-					return err
+			w := &runWatcher{
+				gh:              gh,
+				pollInterval:    pollInterval,
+				maxPollInterval: maxPollInterval,
+				out:             cmd.OutOrStdout(),
+			}
+			dispatched := make([]*dispatchedRun, 0, len(args))
+			for _, r := range args {
+				or := strings.Split(r, "/")
+				if len(or) != 2 {
+					return fmt.Errorf("unexpected format %q, expected %q", r, "org/repo")
 				}
-				for _, w := range workflows {
-					if !queryByName(w, query) {
-						continue
-					}
+				org := or[0]
+				repo := or[1]
 
-					if workflowID == 0 {
-						workflowID = w.GetID()
-					} else {
-						return fmt.Errorf("query %q matched more than one workflow, cancelling", query)
+				id := workflowID
+				if id == 0 {
+					if id, err = findWorkflow(gh, org, repo, query); err != nil {
+						return err
 					}
 				}
-			}
 
-			if workflowID == 0 {
-				return errors.New("unable to locate the workflow requested")
+				if !watch {
+					if err := gh.DispatchWorkflow(org, repo, id, ref, jsonInputs); err != nil {
+						return err
+					}
+					continue
+				}
+				d, err := w.dispatch(org, repo, id, ref, jsonInputs)
+				if err != nil {
+					return err
+				}
+				dispatched = append(dispatched, d)
 			}
 
-			opts := github.CreateWorkflowDispatchEventRequest{
-				Ref:    ref,
-				Inputs: jsonInputs,
+			if !watch {
+				return nil
 			}
-
-			_, err = gh.Client.Actions.CreateWorkflowDispatchEvent(context.Background(), org, repo, workflowID, opts)
-			return err
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			return w.watchAll(ctx, dispatched)
 		},
 	}
 
@@ -172,10 +197,40 @@ func addActionsRunCmd(root *cobra.Command) {
 	cmd.Flags().StringVar(&ref, "ref", "master", "Ref to run workflow from.") // This should be the default branch... but for now we use mostly master.
 	cmd.Flags().Int64Var(&workflowID, "id", 0, "Workflow ID.")
 	cmd.Flags().StringVar(&inputs, "inputs", "", "Workflow inputs.")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Wait for the workflow runs to complete.")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "How long to wait for the workflow runs with --watch.")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 5*time.Second, "Initial interval between polls of the workflow runs with --watch.")
+	cmd.Flags().DurationVar(&maxPollInterval, "max-poll-interval", time.Minute, "Maximum interval between polls of the workflow runs with --watch, the interval doubles while nothing changes.")
 
 	root.AddCommand(cmd)
 }
 
+// findWorkflow returns the ID of the only workflow of org/repo matching the
+// query.
+func findWorkflow(gh *ghutil.GithubClient, org, repo, query string) (int64, error) {
+	workflows, err := gh.ListWorkflows(org, repo)
+	if err != nil {
+		return 0, err
+	}
+	var workflowID int64
+	for _, w := range workflows {
+		if !queryByName(w, query) {
+			continue
+		}
+
+		if workflowID == 0 {
+			workflowID = w.GetID()
+		} else {
+			return 0, fmt.Errorf("query %q matched more than one workflow in %s/%s, cancelling", query, org, repo)
+		}
+	}
+
+	if workflowID == 0 {
+		return 0, fmt.Errorf("unable to locate the workflow requested in %s/%s", org, repo)
+	}
+	return workflowID, nil
+}
+
 // queryByName returns true if the name of the workflow contains the query.
 // Query is case insensitive.
 func queryByName(workflow *github.Workflow, query string) bool {
@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/test-infra/pkg/ghutil"
+)
+
+const (
+	completedStatus = "completed"
+	dispatchEvent   = "workflow_dispatch"
+	// dispatchSkew is how long before the dispatch a new run can be created,
+	// to account for clock differences with GitHub.
+	dispatchSkew = time.Minute
+)
+
+// dispatchedRun is a workflow dispatched for a repo, the run it created is
+// found by comparing the runs before and after the dispatch.
+type dispatchedRun struct {
+	org        string
+	repo       string
+	workflowID int64
+	ref        string
+	// known are the IDs of the runs listed before the dispatch.
+	known      sets.Int64
+	dispatched time.Time
+}
+
+// name returns the org/repo of the dispatched run.
+func (d *dispatchedRun) name() string {
+	return d.org + "/" + d.repo
+}
+
+// runWatcher polls dispatched workflow runs until they complete, writing the
+// status changes of their jobs and steps to out.
+type runWatcher struct {
+	gh ghutil.GithubOperations
+	// pollInterval is the first interval between polls, doubled each time
+	// nothing changed up to maxPollInterval.
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// dispatch lists the existing runs of the workflow and dispatches a new one.
+func (w *runWatcher) dispatch(org, repo string, workflowID int64, ref string, inputs map[string]interface{}) (*dispatchedRun, error) {
+	runs, err := w.gh.ListRecentWorkflowRuns(org, repo, workflowID, ref, dispatchEvent)
+	if err != nil {
+		return nil, err
+	}
+	d := &dispatchedRun{
+		org:        org,
+		repo:       repo,
+		workflowID: workflowID,
+		ref:        ref,
+		known:      sets.NewInt64(),
+		dispatched: time.Now(),
+	}
+	for _, run := range runs {
+		d.known.Insert(run.GetID())
+	}
+	return d, w.gh.DispatchWorkflow(org, repo, workflowID, ref, inputs)
+}
+
+// watchAll waits for all the dispatched runs, and returns an error listing
+// the runs that failed or timed out.
+func (w *runWatcher) watchAll(ctx context.Context, dispatched []*dispatchedRun) error {
+	errs := make([]error, len(dispatched))
+	var wg sync.WaitGroup
+	for i, d := range dispatched {
+		wg.Add(1)
+		go func(i int, d *dispatchedRun) {
+			defer wg.Done()
+			errs[i] = w.watch(ctx, d)
+		}(i, d)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			w.printf("%v\n", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d workflow runs did not succeed", failed, len(dispatched))
+	}
+	return nil
+}
+
+// watch waits for the run created by a dispatch to complete, and returns an
+// error if it did not succeed.
+func (w *runWatcher) watch(ctx context.Context, d *dispatchedRun) error {
+	run, err := w.findRun(ctx, d)
+	if err != nil {
+		return err
+	}
+	w.printf("%s: watching run %s\n", d.name(), run.GetHTMLURL())
+
+	jobs := make(map[int64]string)
+	steps := make(map[string]string)
+	interval := w.pollInterval
+	for {
+		changed := false
+		list, err := w.gh.ListWorkflowJobs(d.org, d.repo, run.GetID())
+		if err != nil {
+			return fmt.Errorf("%s: %w", d.name(), err)
+		}
+		for _, job := range list {
+			if s := state(job.GetStatus(), job.GetConclusion()); jobs[job.GetID()] != s {
+				jobs[job.GetID()] = s
+				changed = true
+				w.printf("%s: job %q %s\n", d.name(), job.GetName(), s)
+			}
+			for _, step := range job.Steps {
+				key := fmt.Sprintf("%d/%d", job.GetID(), step.GetNumber())
+				if s := state(step.GetStatus(), step.GetConclusion()); steps[key] != s {
+					steps[key] = s
+					changed = true
+					w.printf("%s: job %q step %d %q %s\n", d.name(), job.GetName(), step.GetNumber(), step.GetName(), s)
+				}
+			}
+		}
+
+		if run, err = w.gh.GetWorkflowRun(d.org, d.repo, run.GetID()); err != nil {
+			return fmt.Errorf("%s: %w", d.name(), err)
+		}
+		if run.GetStatus() == completedStatus {
+			w.printf("%s: run %s %s\n", d.name(), run.GetHTMLURL(), run.GetConclusion())
+			if !succeeded(run.GetConclusion()) {
+				return fmt.Errorf("%s: run %s %s", d.name(), run.GetHTMLURL(), run.GetConclusion())
+			}
+			return nil
+		}
+
+		if changed {
+			interval = w.pollInterval
+		} else {
+			interval = w.backoff(interval)
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return fmt.Errorf("%s: timed out waiting for run %s: %w", d.name(), run.GetHTMLURL(), err)
+		}
+	}
+}
+
+// findRun polls the runs of the workflow until the run created by the
+// dispatch shows up, the oldest new run if there are several.
+func (w *runWatcher) findRun(ctx context.Context, d *dispatchedRun) (*github.WorkflowRun, error) {
+	interval := w.pollInterval
+	for {
+		runs, err := w.gh.ListRecentWorkflowRuns(d.org, d.repo, d.workflowID, d.ref, dispatchEvent)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.name(), err)
+		}
+		var found *github.WorkflowRun
+		for _, run := range runs {
+			if d.known.Has(run.GetID()) || run.GetCreatedAt().Before(d.dispatched.Add(-dispatchSkew)) {
+				continue
+			}
+			if found == nil || run.GetCreatedAt().Before(found.GetCreatedAt().Time) {
+				found = run
+			}
+		}
+		if found != nil {
+			return found, nil
+		}
+
+		interval = w.backoff(interval)
+		if err := sleep(ctx, interval); err != nil {
+			return nil, fmt.Errorf("%s: timed out waiting for the run of workflow %d to start: %w", d.name(), d.workflowID, err)
+		}
+	}
+}
+
+func (w *runWatcher) backoff(interval time.Duration) time.Duration {
+	if interval *= 2; interval > w.maxPollInterval {
+		return w.maxPollInterval
+	}
+	return interval
+}
+
+func (w *runWatcher) printf(format string, a ...interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, _ = fmt.Fprintf(w.out, format, a...)
+}
+
+// state returns the conclusion of a completed job or step, or its status.
+func state(status, conclusion string) string {
+	if status == completedStatus && conclusion != "" {
+		return conclusion
+	}
+	return status
+}
+
+// succeeded reports if the conclusion of a run is not a failure.
+func succeeded(conclusion string) bool {
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return true
+	}
+	return false
+}
+
+// sleep waits for d, or returns an error if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"knative.dev/test-infra/pkg/ghutil/fakeghutil"
+)
+
+// progressingClient completes the workflow runs with conclusion after they
+// were polled polls times, or never if conclusion is empty.
+type progressingClient struct {
+	*fakeghutil.FakeGithubClient
+	polls      int
+	conclusion string
+}
+
+func (c *progressingClient) GetWorkflowRun(org, repo string, runID int64) (*github.WorkflowRun, error) {
+	run, err := c.FakeGithubClient.GetWorkflowRun(org, repo, runID)
+	if err != nil || c.conclusion == "" {
+		return run, err
+	}
+	if c.polls--; c.polls > 0 {
+		run.Status = github.String("in_progress")
+	} else {
+		run.Status = github.String(completedStatus)
+		run.Conclusion = github.String(c.conclusion)
+	}
+	return run, nil
+}
+
+func TestRunWatcher(t *testing.T) {
+	tests := map[string]struct {
+		conclusion string
+		wantErr    string
+		wantOut    []string
+	}{
+		"completed": {
+			conclusion: "success",
+			wantOut: []string{
+				`knative/serving: watching run fakeurl/knative/serving/actions/runs/1`,
+				`knative/serving: job "build" success`,
+				`knative/serving: job "build" step 1 "Checkout" success`,
+				`knative/serving: run fakeurl/knative/serving/actions/runs/1 success`,
+			},
+		},
+		"failed": {
+			conclusion: "failure",
+			wantErr:    "1 of 1 workflow runs did not succeed",
+			wantOut: []string{
+				`knative/serving: run fakeurl/knative/serving/actions/runs/1 failure`,
+			},
+		},
+		"timeout": {
+			wantErr: "1 of 1 workflow runs did not succeed",
+			wantOut: []string{
+				`knative/serving: timed out waiting for run fakeurl/knative/serving/actions/runs/1: context deadline exceeded`,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fgc := fakeghutil.NewFakeGithubClient()
+			// A previous run of the workflow, which must not be watched.
+			fgc.WorkflowRuns["serving"] = []*github.WorkflowRun{{
+				ID:         github.Int64(100),
+				WorkflowID: github.Int64(42),
+				HeadBranch: github.String("main"),
+				Event:      github.String(dispatchEvent),
+				Status:     github.String("in_progress"),
+				CreatedAt:  &github.Timestamp{Time: time.Now()},
+			}}
+			fgc.WorkflowJobs[1] = []*github.WorkflowJob{{
+				ID:         github.Int64(1),
+				Name:       github.String("build"),
+				Status:     github.String(completedStatus),
+				Conclusion: github.String("success"),
+				Steps: []*github.TaskStep{{
+					Name:       github.String("Checkout"),
+					Number:     github.Int64(1),
+					Status:     github.String(completedStatus),
+					Conclusion: github.String("success"),
+				}},
+			}}
+
+			var out bytes.Buffer
+			w := &runWatcher{
+				gh:              &progressingClient{FakeGithubClient: fgc, polls: 2, conclusion: tt.conclusion},
+				pollInterval:    time.Millisecond,
+				maxPollInterval: 5 * time.Millisecond,
+				out:             &out,
+			}
+			d, err := w.dispatch("knative", "serving", 42, "main", nil)
+			if err != nil {
+				t.Fatal("dispatch() =", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err = w.watchAll(ctx, []*dispatchedRun{d})
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("watchAll() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("watchAll() = %v, want %q", err, tt.wantErr)
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(out.String(), want+"\n") {
+					t.Errorf("watchAll() output = %q, want it to contain %q", out.String(), want)
+				}
+			}
+		})
+	}
+}
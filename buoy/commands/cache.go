@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/test-infra/pkg/git"
+	"knative.dev/test-infra/pkg/golang"
+)
+
+// addCacheFlags adds the flags to cache the git refs and go-import lookups
+// made by the commands, and configures the lookups before any command runs.
+func addCacheFlags(root *cobra.Command) {
+	var (
+		cacheDir string
+		cacheTTL time.Duration
+		snapshot string
+		offline  bool
+	)
+
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		var store git.Store
+		switch {
+		case cacheDir != "" && snapshot != "":
+			return errors.New("--cache-dir and --snapshot can not be used together")
+		case cacheDir != "":
+			if offline {
+				// Offline runs use whatever was cached, no matter how old.
+				cacheTTL = 0
+			}
+			s, err := git.NewDirStore(cacheDir, cacheTTL)
+			if err != nil {
+				return err
+			}
+			store = s
+		case snapshot != "":
+			s, err := git.NewSnapshotStore(snapshot, offline)
+			if err != nil {
+				return err
+			}
+			store = s
+		case offline:
+			return errors.New("--offline requires --cache-dir or --snapshot")
+		default:
+			return nil
+		}
+		golang.UseStore(store, offline)
+		return nil
+	}
+
+	root.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "Cache git refs and go-import lookups in this directory.")
+	root.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", time.Hour, "How long the entries of --cache-dir are used before looking them up again.")
+	root.PersistentFlags().StringVar(&snapshot, "snapshot", "", "Record git refs and go-import lookups to this file, or replay them with --offline.")
+	root.PersistentFlags().BoolVar(&offline, "offline", false, "Never reach the network, only use --cache-dir or --snapshot.")
+}
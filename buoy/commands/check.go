@@ -34,8 +34,10 @@ func addCheckCmd(root *cobra.Command) {
 	var release string
 	var moduleRelease string
 	var rulesetFlag string
-	var ruleset git.RulesetType
+	var rule git.Rule
 	var verbose bool
+	var output string
+	var verify bool
 
 	var cmd = &cobra.Command{
 		Use:   "check go.mod",
@@ -52,18 +54,29 @@ Rulesets,
   Branch           check requires all dependencies to have a release branch.
   ReleaseOrBranch  check will use rule (Release || Branch).
 
+The ruleset can also be a semver constraint, only tagged releases matching the
+constraint are selected, choosing the one with the highest version:
+  ">=1.9.0 <1.11.0"  a semver range
+  "N-1"              the latest patch of the previous minor of the module
+                     release, "N" is the latest patch of the same minor
+
+With --verify, check also cross-checks the go.sum and vendor/modules.txt files
+next to go.mod, and fails if they are missing or do not match the version of a
+dependency required by go.mod, i.e. after floating the dependencies without
+running "go mod tidy" and "go mod vendor".
+
 `,
 		Args: cobra.ExactArgs(1),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Validation
-			ruleset = git.Ruleset(rulesetFlag)
-			if ruleset == git.InvalidRule {
-				return fmt.Errorf("invalid ruleset, please select one of: [%s]", strings.Join(git.Rulesets(), ", "))
+			var err error
+			if rule, err = git.ParseRule(rulesetFlag); err != nil {
+				return err
 			}
 			if moduleRelease == "" {
 				moduleRelease = release
 			}
-			return nil
+			return validateOutput(output)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			gomodFile := args[0]
@@ -77,8 +90,38 @@ Rulesets,
 			if err != nil {
 				return err
 			}
-			err = gomod.Check(gomodFile, release, moduleRelease, selector, ruleset, out)
-			if errors.Is(err, gomod.DependencyErr) {
+
+			if output != textOutput {
+				results, err := gomod.CheckResults(gomodFile, release, moduleRelease, selector, rule)
+				if err != nil {
+					return err
+				}
+				if verify {
+					_, mismatches, err := gomod.VerifyResults(gomodFile, selector)
+					if err != nil {
+						return err
+					}
+					for _, result := range results {
+						result.Mismatches = mismatches
+						result.Ready = result.Ready && len(mismatches) == 0
+					}
+				}
+				if err := writeStructured(cmd.OutOrStdout(), output, results); err != nil {
+					return err
+				}
+				for _, result := range results {
+					if !result.Ready {
+						os.Exit(1)
+					}
+				}
+				return nil
+			}
+
+			err = gomod.Check(gomodFile, release, moduleRelease, selector, rule, out)
+			if err == nil && verify {
+				err = gomod.Verify(gomodFile, selector, out)
+			}
+			if errors.Is(err, gomod.DependencyErr) || errors.Is(err, gomod.VerifyErr) {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), err.Error())
 				os.Exit(1)
 			}
@@ -93,7 +136,9 @@ Rulesets,
 	_ = cmd.MarkFlagRequired("release")
 	cmd.Flags().StringVarP(&moduleRelease, "module-release", "m", "", "if the go modules are a different release set than the release, use --module-release, should be '<major>.<minor>' (i.e.: 0.12 or v0.12)")
 	cmd.Flags().StringVar(&rulesetFlag, "ruleset", git.ReleaseOrReleaseBranchRule.String(), fmt.Sprintf("The ruleset to evaluate the dependency refs. Rulesets: [%s]", strings.Join(git.Rulesets(), ", ")))
+	cmd.Flags().BoolVar(&verify, "verify", false, "Verify go.sum and vendor/modules.txt match the dependencies in go.mod.")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print verbose output.")
+	addOutputFlag(cmd, &output)
 
 	root.AddCommand(cmd)
 }
@@ -25,12 +25,16 @@ func New() *cobra.Command {
 		Short: "Introspect go module dependencies.",
 	}
 
+	addCacheFlags(buoyCmd)
+
 	addFloatCmd(buoyCmd)
 	addNeedsCmd(buoyCmd)
 	addCheckCmd(buoyCmd)
 	addExistsCmd(buoyCmd)
 	addReposCmd(buoyCmd)
 	addActionsCmd(buoyCmd)
+	addGraphCmd(buoyCmd)
+	addReleaseOrderCmd(buoyCmd)
 
 	return buoyCmd
 }
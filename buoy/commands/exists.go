@@ -32,6 +32,7 @@ func addExistsCmd(root *cobra.Command) {
 		moduleRelease string
 		verbose       bool
 		tag           bool
+		output        string
 	)
 
 	var cmd = &cobra.Command{
@@ -42,7 +43,7 @@ func addExistsCmd(root *cobra.Command) {
 			if moduleRelease == "" {
 				moduleRelease = release
 			}
-			return nil
+			return validateOutput(output)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			gomodFile := args[0]
@@ -57,7 +58,11 @@ func addExistsCmd(root *cobra.Command) {
 				return err
 			}
 
-			if tag {
+			if output != textOutput {
+				if err := writeStructured(cmd.OutOrStdout(), output, meta); err != nil {
+					return err
+				}
+			} else if tag {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), meta.Release)
 			}
 
@@ -74,6 +79,7 @@ func addExistsCmd(root *cobra.Command) {
 	cmd.Flags().StringVarP(&moduleRelease, "module-release", "m", "", "if the go modules are a different release set than the release, use --module-release, should be '<major>.<minor>' (i.e.: 0.12 or v0.12)")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print verbose output (stderr)")
 	cmd.Flags().BoolVarP(&tag, "next", "t", false, "Print the next release tag (stdout)")
+	addOutputFlag(cmd, &output)
 
 	root.AddCommand(cmd)
 }
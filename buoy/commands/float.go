@@ -17,7 +17,9 @@ limitations under the License.
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -32,7 +34,11 @@ func addFloatCmd(root *cobra.Command) {
 		release       string
 		moduleRelease string
 		rulesetFlag   string
-		ruleset       git.RulesetType
+		rule          git.Rule
+		write         bool
+		gowork        string
+		dryRun        bool
+		output        string
 	)
 
 	var cmd = &cobra.Command{
@@ -56,19 +62,42 @@ Rulesets,
   Branch           release branches
   ReleaseOrBranch  tagged releases, release branch
 
+The ruleset can also be a semver constraint, only tagged releases matching the
+constraint are selected, choosing the one with the highest version:
+  ">=1.9.0 <1.11.0"  a semver range
+  "N-1"              the latest patch of the previous minor of the module
+                     release, "N" is the latest patch of the same minor
+
 For rulesets that that restrict the selection process, no ref is selected.
+
+With --write, the selected refs are written to the go.mod file instead of
+being printed. Branches are resolved to pseudo-versions like "go get" does.
+Comments and replace directives are preserved. Use --gowork to also update
+the go.work replace directives that pin a selected module, and --dry-run to
+print a unified diff of the changes instead of writing them. Branches are
+resolved by the go command, which doesn't use --cache-dir or --snapshot, so
+--write can not be used with --offline.
 `,
 		Args: cobra.ExactArgs(1),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Validation
-			ruleset = git.Ruleset(rulesetFlag)
-			if ruleset == git.InvalidRule {
-				return fmt.Errorf("invalid ruleset, please select one of: [%s]", strings.Join(git.Rulesets(), ", "))
+			var err error
+			if rule, err = git.ParseRule(rulesetFlag); err != nil {
+				return err
 			}
 			if moduleRelease == "" {
 				moduleRelease = release
 			}
-			return nil
+			if !write && (gowork != "" || dryRun) {
+				return errors.New("--gowork and --dry-run require --write")
+			}
+			if write && output != textOutput {
+				return errors.New("--output can not be used with --write")
+			}
+			if offline, _ := cmd.Flags().GetBool("offline"); offline && write {
+				return errors.New("--offline can not be used with --write")
+			}
+			return validateOutput(output)
 		},
 
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -78,11 +107,37 @@ For rulesets that that restrict the selection process, no ref is selected.
 			if err != nil {
 				return err
 			}
-			refs, err := gomod.Float(gomodFile, release, moduleRelease, selector, ruleset)
+			if output != textOutput {
+				deps, err := gomod.FloatDependencies(gomodFile, release, moduleRelease, selector, rule)
+				if err != nil {
+					return err
+				}
+				return writeStructured(cmd.OutOrStdout(), output, deps)
+			}
+
+			refs, err := gomod.Float(gomodFile, release, moduleRelease, selector, rule)
 			if err != nil {
 				return err
 			}
 
+			if write {
+				changes, err := gomod.UpdateRefs(gomodFile, gowork, refs, gomod.GoListResolver(filepath.Dir(gomodFile)))
+				if err != nil {
+					return err
+				}
+				for _, c := range changes {
+					if dryRun {
+						err = c.Diff(cmd.OutOrStdout())
+					} else {
+						err = c.Write()
+					}
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
 			for _, r := range refs {
 				if r != "" {
 					_, _ = fmt.Fprintln(cmd.OutOrStdout(), r)
@@ -97,6 +152,10 @@ For rulesets that that restrict the selection process, no ref is selected.
 	_ = cmd.MarkFlagRequired("release")
 	cmd.Flags().StringVarP(&moduleRelease, "module-release", "m", "", "if the go modules are a different release set than the release, use --module-release, should be '<major>.<minor>' (i.e.: 0.12 or v0.12)")
 	cmd.Flags().StringVar(&rulesetFlag, "ruleset", git.AnyRule.String(), fmt.Sprintf("The ruleset to evaluate the dependency refs. Rulesets: [%s]", strings.Join(git.Rulesets(), ", ")))
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "Write the selected refs to the go.mod file instead of printing them.")
+	cmd.Flags().StringVar(&gowork, "gowork", "", "go.work file to update along with the go.mod file, requires --write.")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a unified diff of the changes instead of writing them, requires --write.")
+	addOutputFlag(cmd, &output)
 
 	root.AddCommand(cmd)
 }
@@ -0,0 +1,33 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestFloatWriteOffline(t *testing.T) {
+	cmd := New()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"--cache-dir", t.TempDir(), "--offline", "float", "go.mod", "--release", "1.2", "--write"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--offline can not be used with --write") {
+		t.Errorf("Execute() = %v, want an error for --offline with --write", err)
+	}
+}
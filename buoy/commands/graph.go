@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/test-infra/pkg/gomod"
+)
+
+func addGraphCmd(root *cobra.Command) {
+	var (
+		domain string
+		output string
+	)
+
+	var cmd = &cobra.Command{
+		Use:   "graph go.mod|dir [go.mod|dir...]",
+		Short: "Print the dependency graph between modules for a domain.",
+		Long: `
+The graph command reads the given go.mod files, or every go.mod file found
+under the given directories, and prints the graph of direct dependencies
+between modules in the domain. Each edge is labeled with the version that is
+required by the dependent module.
+
+Outputs,
+  dot   Graphviz DOT, render with: buoy graph ... | dot -Tsvg > graph.svg
+  json  modules and edges as a JSON document
+`,
+		Args: cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validation
+			if output != "dot" && output != "json" {
+				return fmt.Errorf("invalid output %q, please select one of: [dot, json]", output)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gomods, err := gomod.FindModules(args)
+			if err != nil {
+				return err
+			}
+
+			selector, err := gomod.DomainSelector(domain)
+			if err != nil {
+				return err
+			}
+			graph, err := gomod.ModuleGraph(gomods, selector)
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(graph)
+			}
+			return graph.WriteDot(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&domain, "domain", "d", "knative.dev", "domain filter (i.e. knative.dev)")
+	cmd.Flags().StringVarP(&output, "output", "o", "dot", "Output format. One of: [dot, json]")
+
+	root.AddCommand(cmd)
+}
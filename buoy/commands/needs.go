@@ -24,13 +24,25 @@ import (
 	"knative.dev/test-infra/pkg/gomod"
 )
 
+// needsResult is the structured output of the needs command.
+type needsResult struct {
+	// Modules maps each module to its dependencies in the domain.
+	Modules map[string][]string `json:"modules"`
+	// Dependencies is the list of unique dependencies in the domain.
+	Dependencies []string `json:"dependencies"`
+}
+
 func addNeedsCmd(root *cobra.Command) {
 	var domain string
+	var output string
 
 	var cmd = &cobra.Command{
 		Use:   "needs go.mod",
 		Short: "Find dependencies based on a base import domain.",
 		Args:  cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutput(output)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			gomods := args
 
@@ -38,11 +50,18 @@ func addNeedsCmd(root *cobra.Command) {
 			if err != nil {
 				return err
 			}
-			_, packages, err := gomod.Modules(gomods, selector)
+			modules, packages, err := gomod.Modules(gomods, selector)
 			if err != nil {
 				return err
 			}
 
+			if output != textOutput {
+				return writeStructured(cmd.OutOrStdout(), output, needsResult{
+					Modules:      modules,
+					Dependencies: packages,
+				})
+			}
+
 			for _, p := range packages {
 				if p != "" {
 					_, _ = fmt.Fprintln(cmd.OutOrStdout(), p)
@@ -54,6 +73,7 @@ func addNeedsCmd(root *cobra.Command) {
 
 	cmd.Flags().StringVarP(&domain, "domain", "d", "", "domain filter (i.e. knative.dev) [required]")
 	_ = cmd.MarkFlagRequired("domain")
+	addOutputFlag(cmd, &output)
 
 	root.AddCommand(cmd)
 }
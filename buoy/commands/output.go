@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	textOutput = "text"
+	jsonOutput = "json"
+	yamlOutput = "yaml"
+)
+
+// addOutputFlag adds the --output flag for the text, json and yaml formats.
+func addOutputFlag(cmd *cobra.Command, output *string) {
+	cmd.Flags().StringVarP(output, "output", "o", textOutput, fmt.Sprintf("Output format. One of: [%s, %s, %s]", textOutput, jsonOutput, yamlOutput))
+}
+
+// validateOutput returns an error if output is not a known format.
+func validateOutput(output string) error {
+	switch output {
+	case textOutput, jsonOutput, yamlOutput:
+		return nil
+	}
+	return fmt.Errorf("invalid output %q, please select one of: [%s, %s, %s]", output, textOutput, jsonOutput, yamlOutput)
+}
+
+// writeStructured writes v to out in the json or yaml output format.
+func writeStructured(out io.Writer, output string, v interface{}) error {
+	switch output {
+	case jsonOutput:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case yamlOutput:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(b)
+		return err
+	}
+	return fmt.Errorf("output %q is not a structured format", output)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/test-infra/pkg/gomod"
+)
+
+func addReleaseOrderCmd(root *cobra.Command) {
+	var (
+		domain        string
+		release       string
+		moduleRelease string
+		output        string
+		verbose       bool
+	)
+
+	var cmd = &cobra.Command{
+		Use:   "release-order go.mod|dir [go.mod|dir...]",
+		Short: "Compute the order modules have to be released in for a given release.",
+		Long: `
+The release-order command reads the given go.mod files, or every go.mod file
+found under the given directories, and groups the modules of the domain into
+waves. A module only depends on modules from earlier waves, so all the modules
+of a wave can be released in parallel once the previous waves are done.
+
+Each module is marked with ✔ if its release branch already exists, or ✘ if it
+does not. A dependency cycle between modules is reported as an error.
+`,
+		Args: cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validation
+			if err := validateOutput(output); err != nil {
+				return err
+			}
+			if moduleRelease == "" {
+				moduleRelease = release
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gomods, err := gomod.FindModules(args)
+			if err != nil {
+				return err
+			}
+
+			var out io.Writer
+			if verbose {
+				out = cmd.OutOrStderr()
+			}
+
+			selector, err := gomod.DomainSelector(domain)
+			if err != nil {
+				return err
+			}
+			plan, err := gomod.ReleasePlan(gomods, release, moduleRelease, selector, out)
+			if err != nil {
+				return err
+			}
+
+			if output != textOutput {
+				return writeStructured(cmd.OutOrStdout(), output, plan)
+			}
+			for i, wave := range plan {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wave %d\n", i+1)
+				for _, meta := range wave {
+					mark := "✘ "
+					if meta.ReleaseBranchExists {
+						mark = "✔ "
+					}
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), mark, meta.Module, meta.ReleaseBranch)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&domain, "domain", "d", "knative.dev", "domain filter (i.e. knative.dev)")
+	cmd.Flags().StringVarP(&release, "release", "r", "", "release should be '<major>.<minor>' (i.e.: 1.23 or v1.23) [required]")
+	_ = cmd.MarkFlagRequired("release")
+	cmd.Flags().StringVarP(&moduleRelease, "module-release", "m", "", "if the go modules are a different release set than the release, use --module-release, should be '<major>.<minor>' (i.e.: 0.12 or v0.12)")
+	addOutputFlag(cmd, &output)
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print verbose output (stderr)")
+
+	root.AddCommand(cmd)
+}
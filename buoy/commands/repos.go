@@ -18,19 +18,145 @@ package commands
 
 import (
 	"fmt"
+	"sort"
+	"text/tabwriter"
 
+	"github.com/google/go-github/v32/github"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+
 	"knative.dev/test-infra/pkg/ghutil"
+	"knative.dev/test-infra/pkg/git"
+)
+
+const (
+	allVisibility     = "all"
+	publicVisibility  = "public"
+	privateVisibility = "private"
 )
 
+// repoInfo is a repository of a GitHub organization.
+type repoInfo struct {
+	Org                 string   `json:"org"`
+	Name                string   `json:"name"`
+	DefaultBranch       string   `json:"defaultBranch"`
+	LatestReleaseBranch string   `json:"latestReleaseBranch,omitempty"`
+	Visibility          string   `json:"visibility"`
+	Archived            bool     `json:"archived"`
+	Fork                bool     `json:"fork"`
+	Topics              []string `json:"topics,omitempty"`
+}
+
+// repoFilter selects the repositories to list.
+type repoFilter struct {
+	excludeArchived bool
+	excludeForks    bool
+	visibility      string
+	topics          []string
+	hasGoMod        bool
+}
+
+// match reports if a repository matches the filter, except for hasGoMod
+// which needs an extra call.
+func (f *repoFilter) match(repo *github.Repository) bool {
+	if f.excludeArchived && repo.GetArchived() {
+		return false
+	}
+	if f.excludeForks && repo.GetFork() {
+		return false
+	}
+	if f.visibility != allVisibility && f.visibility != visibility(repo) {
+		return false
+	}
+	return sets.NewString(repo.Topics...).HasAll(f.topics...)
+}
+
+func visibility(repo *github.Repository) string {
+	if repo.GetPrivate() {
+		return privateVisibility
+	}
+	return publicVisibility
+}
+
+// listRepos lists the repositories of org matching the filter, sorted by
+// name. The latest release branch is only looked up if withBranches is set.
+func listRepos(gh ghutil.GithubOperations, org string, filter *repoFilter, withBranches bool) ([]*repoInfo, error) {
+	repos, err := gh.ListRepositories(org)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*repoInfo, 0, len(repos))
+	for _, repo := range repos {
+		if !filter.match(repo) {
+			continue
+		}
+		if filter.hasGoMod {
+			has, err := gh.HasFile(org, repo.GetName(), "go.mod")
+			if err != nil {
+				return nil, err
+			}
+			if !has {
+				continue
+			}
+		}
+
+		info := &repoInfo{
+			Org:           org,
+			Name:          repo.GetName(),
+			DefaultBranch: repo.GetDefaultBranch(),
+			Visibility:    visibility(repo),
+			Archived:      repo.GetArchived(),
+			Fork:          repo.GetFork(),
+			Topics:        repo.Topics,
+		}
+		if withBranches {
+			branches, err := gh.ListBranches(org, info.Name)
+			if err != nil {
+				return nil, err
+			}
+			names := make([]string, 0, len(branches))
+			for _, b := range branches {
+				names = append(names, b.GetName())
+			}
+			info.LatestReleaseBranch, _ = git.LatestReleaseBranch(names)
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos, nil
+}
+
 func addReposCmd(root *cobra.Command) {
 
 	var tokenPath string
+	var filter repoFilter
+	var wide bool
+	var output string
 
 	var cmd = &cobra.Command{
 		Use:   "repos org1 [org2 org3...]",
 		Short: "List the repos for a list of GitHub organizations.",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `
+The repos command lists the repos of the given GitHub organizations, sorted by
+name. The repos can be filtered on being archived or forked, on visibility, on
+topics, and on having a go.mod file at the root of the default branch.
+
+With --wide, the default branch and the latest release branch of each repo are
+printed as well. The json and yaml outputs always include them.
+`,
+		Args: cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validation
+			switch filter.visibility {
+			case allVisibility, publicVisibility, privateVisibility:
+			default:
+				return fmt.Errorf("invalid visibility %q, please select one of: [%s, %s, %s]", filter.visibility, allVisibility, publicVisibility, privateVisibility)
+			}
+			return validateOutput(output)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			orgs := args
 			gh, err := ghutil.NewGithubClient(tokenPath)
@@ -39,21 +165,41 @@ func addReposCmd(root *cobra.Command) {
 			}
 
 			// for all given orgs, list the repos.
+			all := make([]*repoInfo, 0)
 			for _, org := range orgs {
-				repos, err := gh.ListRepos(org)
+				repos, err := listRepos(gh, org, &filter, wide || output != textOutput)
 				if err != nil {
 					return err
 				}
-				for _, repo := range repos {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), org+"/"+repo)
-				}
+				all = append(all, repos...)
 			}
 
-			return nil
+			if output != textOutput {
+				return writeStructured(cmd.OutOrStdout(), output, all)
+			}
+			if !wide {
+				for _, repo := range all {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), repo.Org+"/"+repo.Name)
+				}
+				return nil
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "REPO\tDEFAULT BRANCH\tLATEST RELEASE BRANCH")
+			for _, repo := range all {
+				_, _ = fmt.Fprintf(w, "%s/%s\t%s\t%s\n", repo.Org, repo.Name, repo.DefaultBranch, repo.LatestReleaseBranch)
+			}
+			return w.Flush()
 		},
 	}
 
 	cmd.Flags().StringVarP(&tokenPath, "token-path", "t", "", "GitHub token file path.")
+	cmd.Flags().BoolVar(&filter.excludeArchived, "exclude-archived", false, "Do not list archived repos.")
+	cmd.Flags().BoolVar(&filter.excludeForks, "exclude-forks", false, "Do not list forked repos.")
+	cmd.Flags().StringVar(&filter.visibility, "visibility", allVisibility, fmt.Sprintf("Only list repos with this visibility. One of: [%s, %s, %s]", allVisibility, publicVisibility, privateVisibility))
+	cmd.Flags().StringSliceVar(&filter.topics, "topic", nil, "Only list repos with all of these topics.")
+	cmd.Flags().BoolVar(&filter.hasGoMod, "has-gomod", false, "Only list repos with a go.mod file at the root of the default branch.")
+	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Print the default branch and the latest release branch of each repo.")
+	addOutputFlag(cmd, &output)
 
 	root.AddCommand(cmd)
 }
@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"knative.dev/test-infra/pkg/ghutil/fakeghutil"
+)
+
+func newFakeRepo(name string, private, archived, fork bool, topics ...string) *github.Repository {
+	return &github.Repository{
+		Name:          github.String(name),
+		DefaultBranch: github.String("main"),
+		Private:       github.Bool(private),
+		Archived:      github.Bool(archived),
+		Fork:          github.Bool(fork),
+		Topics:        topics,
+	}
+}
+
+func newFakeReposClient() *fakeghutil.FakeGithubClient {
+	fgc := fakeghutil.NewFakeGithubClient()
+	fgc.Repositories = []*github.Repository{
+		newFakeRepo("serving", false, false, false, "knative", "go"),
+		newFakeRepo("archived", false, true, false, "knative"),
+		newFakeRepo("fork", false, false, true),
+		newFakeRepo("internal", true, false, false, "go"),
+		newFakeRepo("docs", false, false, false, "knative"),
+	}
+	fgc.Files = map[string][]string{
+		"serving":  {"go.mod", "README.md"},
+		"internal": {"go.mod"},
+		"docs":     {"README.md", "hack/go.mod"},
+	}
+	fgc.Branches = map[string][]*github.Branch{
+		"serving": {
+			{Name: github.String("main")},
+			{Name: github.String("release-1.9")},
+			{Name: github.String("release-1.10")},
+		},
+		"docs": {{Name: github.String("main")}},
+	}
+	return fgc
+}
+
+func TestListRepos(t *testing.T) {
+	tests := map[string]struct {
+		filter repoFilter
+		want   []string
+	}{
+		"all": {
+			filter: repoFilter{visibility: allVisibility},
+			want:   []string{"archived", "docs", "fork", "internal", "serving"},
+		},
+		"exclude archived and forks": {
+			filter: repoFilter{visibility: allVisibility, excludeArchived: true, excludeForks: true},
+			want:   []string{"docs", "internal", "serving"},
+		},
+		"public": {
+			filter: repoFilter{visibility: publicVisibility},
+			want:   []string{"archived", "docs", "fork", "serving"},
+		},
+		"private": {
+			filter: repoFilter{visibility: privateVisibility},
+			want:   []string{"internal"},
+		},
+		"all topics": {
+			filter: repoFilter{visibility: allVisibility, topics: []string{"knative", "go"}},
+			want:   []string{"serving"},
+		},
+		"has go.mod at the root": {
+			filter: repoFilter{visibility: allVisibility, hasGoMod: true},
+			want:   []string{"internal", "serving"},
+		},
+		"has go.mod and public": {
+			filter: repoFilter{visibility: publicVisibility, hasGoMod: true},
+			want:   []string{"serving"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repos, err := listRepos(newFakeReposClient(), "knative", &tt.filter, false)
+			if err != nil {
+				t.Fatal("listRepos() =", err)
+			}
+			got := make([]string, 0, len(repos))
+			for _, repo := range repos {
+				got = append(got, repo.Name)
+				if repo.Org != "knative" || repo.LatestReleaseBranch != "" {
+					t.Errorf("listRepos() repo = %+v, want org knative and no release branch", repo)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listRepos() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListReposWithBranches(t *testing.T) {
+	filter := repoFilter{visibility: publicVisibility, topics: []string{"knative"}, excludeArchived: true}
+	repos, err := listRepos(newFakeReposClient(), "knative", &filter, true)
+	if err != nil {
+		t.Fatal("listRepos() =", err)
+	}
+	want := []*repoInfo{{
+		Org:           "knative",
+		Name:          "docs",
+		DefaultBranch: "main",
+		Visibility:    publicVisibility,
+		Topics:        []string{"knative"},
+	}, {
+		Org:                 "knative",
+		Name:                "serving",
+		DefaultBranch:       "main",
+		LatestReleaseBranch: "release-1.10",
+		Visibility:          publicVisibility,
+		Topics:              []string{"knative", "go"},
+	}}
+	if !reflect.DeepEqual(repos, want) {
+		t.Errorf("listRepos() = %+v, want %+v", repos, want)
+	}
+}
@@ -19,6 +19,11 @@ limitations under the License.
 package ghutil
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
 	"github.com/google/go-github/v32/github"
 )
 
@@ -46,3 +51,165 @@ func (gc *GithubClient) ListWorkflows(org, repo string) ([]*github.Workflow, err
 	}
 	return res, err
 }
+
+// DispatchWorkflow triggers a run of a workflow with workflow_dispatch for a
+// given org/repo.
+func (gc *GithubClient) DispatchWorkflow(org, repo string, workflowID int64, ref string, inputs map[string]interface{}) error {
+	opts := github.CreateWorkflowDispatchEventRequest{
+		Ref:    ref,
+		Inputs: inputs,
+	}
+	_, err := gc.retry(
+		fmt.Sprintf("dispatching workflow '%s %s %d'", org, repo, workflowID),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			return gc.Client.Actions.CreateWorkflowDispatchEvent(ctx, org, repo, workflowID, opts)
+		},
+	)
+	return err
+}
+
+// ListRecentWorkflowRuns lists the most recent runs of a workflow for a given
+// org/repo, filtered by branch and event if not empty. Only the first page of
+// runs is returned, the most recent first.
+func (gc *GithubClient) ListRecentWorkflowRuns(org, repo string, workflowID int64, branch, event string) ([]*github.WorkflowRun, error) {
+	var res []*github.WorkflowRun
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Event:       event,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	_, err := gc.retry(
+		fmt.Sprintf("listing runs of workflow '%s %s %d'", org, repo, workflowID),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			runs, resp, err := gc.Client.Actions.ListWorkflowRunsByID(ctx, org, repo, workflowID, opts)
+			if err == nil {
+				res = runs.WorkflowRuns
+			}
+			return resp, err
+		},
+	)
+	return res, err
+}
+
+// GetWorkflowRun gets a workflow run by run ID
+func (gc *GithubClient) GetWorkflowRun(org, repo string, runID int64) (*github.WorkflowRun, error) {
+	var res *github.WorkflowRun
+	_, err := gc.retry(
+		fmt.Sprintf("getting workflow run '%s %s %d'", org, repo, runID),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			var resp *github.Response
+			var err error
+			res, resp, err = gc.Client.Actions.GetWorkflowRunByID(ctx, org, repo, runID)
+			return resp, err
+		},
+	)
+	return res, err
+}
+
+// ListWorkflowJobs lists the jobs, with their steps, of the latest attempt of
+// a workflow run
+func (gc *GithubClient) ListWorkflowJobs(org, repo string, runID int64) ([]*github.WorkflowJob, error) {
+	jobListOptions := &github.ListWorkflowJobsOptions{}
+	genericList, err := gc.depaginate(
+		fmt.Sprintf("listing jobs of workflow run '%s %s %d'", org, repo, runID),
+		maxRetryCount,
+		&jobListOptions.ListOptions,
+		func() ([]interface{}, *github.Response, error) {
+			jobs, resp, err := gc.Client.Actions.ListWorkflowJobs(ctx, org, repo, runID, jobListOptions)
+			var interfaceList []interface{}
+			if nil == err {
+				for _, job := range jobs.Jobs {
+					interfaceList = append(interfaceList, job)
+				}
+			}
+			return interfaceList, resp, err
+		},
+	)
+	res := make([]*github.WorkflowJob, len(genericList))
+	for i, elem := range genericList {
+		res[i] = elem.(*github.WorkflowJob)
+	}
+	return res, err
+}
+
+// ListWorkflowRunsByFileName lists a page, starting at 1, of the completed
+// runs of a workflow, identified by its file name (i.e. "e2e.yaml"), for a
+// given org/repo, filtered by branch if not empty. Runs are listed the most
+// recent first, 100 per page, and a page past the last run is empty.
+func (gc *GithubClient) ListWorkflowRunsByFileName(org, repo, fileName, branch string, page int) ([]*github.WorkflowRun, error) {
+	var res []*github.WorkflowRun
+	opts := &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Status:      "completed",
+		ListOptions: github.ListOptions{Page: page, PerPage: 100},
+	}
+	_, err := gc.retry(
+		fmt.Sprintf("listing runs of workflow '%s %s %s', page %d", org, repo, fileName, page),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			runs, resp, err := gc.Client.Actions.ListWorkflowRunsByFileName(ctx, org, repo, fileName, opts)
+			if err == nil {
+				res = runs.WorkflowRuns
+			}
+			return resp, err
+		},
+	)
+	return res, err
+}
+
+// ListWorkflowRunArtifacts lists the artifacts uploaded by a workflow run
+func (gc *GithubClient) ListWorkflowRunArtifacts(org, repo string, runID int64) ([]*github.Artifact, error) {
+	listOptions := &github.ListOptions{}
+	genericList, err := gc.depaginate(
+		fmt.Sprintf("listing artifacts of workflow run '%s %s %d'", org, repo, runID),
+		maxRetryCount,
+		listOptions,
+		func() ([]interface{}, *github.Response, error) {
+			artifacts, resp, err := gc.Client.Actions.ListWorkflowRunArtifacts(ctx, org, repo, runID, listOptions)
+			var interfaceList []interface{}
+			if nil == err {
+				for _, artifact := range artifacts.Artifacts {
+					interfaceList = append(interfaceList, artifact)
+				}
+			}
+			return interfaceList, resp, err
+		},
+	)
+	res := make([]*github.Artifact, len(genericList))
+	for i, elem := range genericList {
+		res[i] = elem.(*github.Artifact)
+	}
+	return res, err
+}
+
+// DownloadArtifact downloads the zip archive of a workflow run artifact
+func (gc *GithubClient) DownloadArtifact(org, repo string, artifactID int64) ([]byte, error) {
+	var u *url.URL
+	_, err := gc.retry(
+		fmt.Sprintf("getting download URL of artifact '%s %s %d'", org, repo, artifactID),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			var resp *github.Response
+			var err error
+			u, resp, err = gc.Client.Actions.DownloadArtifact(ctx, org, repo, artifactID, true)
+			return resp, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The download URL is pre-signed, it does not need the GitHub token.
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed downloading artifact '%s %s %d': %s", org, repo, artifactID, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
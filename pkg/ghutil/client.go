@@ -45,10 +45,16 @@ var (
 type GithubOperations interface {
 	GetGithubUser() (*github.User, error)
 	ListRepos(org string) ([]string, error)
+	ListRepositories(org string) ([]*github.Repository, error)
+	HasFile(org, repo, path string) (bool, error)
+	GetFile(org, repo, path, ref string) ([]byte, string, error)
+	CreateBranch(org, repo, branch, base string) error
+	UpdateFile(org, repo, branch, path, message string, contents []byte, sha string) error
 	ListIssuesByRepo(org, repo string, labels []string) ([]*github.Issue, error)
 	CreateIssue(org, repo, title, body string) (*github.Issue, error)
 	CloseIssue(org, repo string, issueNumber int) error
 	ReopenIssue(org, repo string, issueNumber int) error
+	EditIssue(org, repo string, issueNumber int, title, body string) error
 	ListComments(org, repo string, issueNumber int) ([]*github.IssueComment, error)
 	GetComment(org, repo string, commentID int64) (*github.IssueComment, error)
 	CreateComment(org, repo string, issueNumber int, commentBody string) (*github.IssueComment, error)
@@ -59,12 +65,17 @@ type GithubOperations interface {
 	GetPullRequest(org, repo string, ID int) (*github.PullRequest, error)
 	GetPullRequestByCommitID(org, repo, commitID string) (*github.PullRequest, error)
 	EditPullRequest(org, repo string, ID int, title, body string) (*github.PullRequest, error)
+	ClosePullRequest(org, repo string, ID int) error
 	ListPullRequests(org, repo, head, base string) ([]*github.PullRequest, error)
 	ListCommits(org, repo string, ID int) ([]*github.RepositoryCommit, error)
 	ListFiles(org, repo string, ID int) ([]*github.CommitFile, error)
 	CreatePullRequest(org, repo, head, base, title, body string) (*github.PullRequest, error)
 	EnsureLabelForPullRequest(org, repo string, ID int, label string) error
 	ListBranches(org, repo string) ([]*github.Branch, error)
+	DispatchWorkflow(org, repo string, workflowID int64, ref string, inputs map[string]interface{}) error
+	ListRecentWorkflowRuns(org, repo string, workflowID int64, branch, event string) ([]*github.WorkflowRun, error)
+	GetWorkflowRun(org, repo string, runID int64) (*github.WorkflowRun, error)
+	ListWorkflowJobs(org, repo string, runID int64) ([]*github.WorkflowJob, error)
 }
 
 // GithubClient provides methods to perform github operations
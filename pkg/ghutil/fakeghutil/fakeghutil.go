@@ -19,9 +19,11 @@ limitations under the License.
 package fakeghutil
 
 import (
+	"crypto/sha1"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v32/github"
 
@@ -32,12 +34,17 @@ import (
 type FakeGithubClient struct {
 	User         *github.User
 	Repos        []string
-	Issues       map[string]map[int]*github.Issue       // map of repo: map of issueNumber: issues
-	Comments     map[int]map[int64]*github.IssueComment // map of issueNumber: map of commentID: comments
-	PullRequests map[string]map[int]*github.PullRequest // map of repo: map of PullRequest Number: pullrequests
-	PRCommits    map[int][]*github.RepositoryCommit     // map of PR number: slice of commits
-	CommitFiles  map[string][]*github.CommitFile        // map of commit SHA: slice of files
-	Branches     map[string][]*github.Branch            // map of repo: branches
+	Repositories []*github.Repository                    // repos with their metadata
+	Files        map[string][]string                     // map of repo: file paths
+	FileContents map[string]map[string]map[string][]byte // map of repo: map of branch: map of file path: contents
+	Issues       map[string]map[int]*github.Issue        // map of repo: map of issueNumber: issues
+	Comments     map[int]map[int64]*github.IssueComment  // map of issueNumber: map of commentID: comments
+	PullRequests map[string]map[int]*github.PullRequest  // map of repo: map of PullRequest Number: pullrequests
+	PRCommits    map[int][]*github.RepositoryCommit      // map of PR number: slice of commits
+	CommitFiles  map[string][]*github.CommitFile         // map of commit SHA: slice of files
+	Branches     map[string][]*github.Branch             // map of repo: branches
+	WorkflowRuns map[string][]*github.WorkflowRun        // map of repo: workflow runs
+	WorkflowJobs map[int64][]*github.WorkflowJob         // map of run ID: jobs
 
 	NextNumber int    // number to be assigned to next newly created issue/comment
 	BaseURL    string // base URL of Github
@@ -51,6 +58,10 @@ func NewFakeGithubClient() *FakeGithubClient {
 		PullRequests: make(map[string]map[int]*github.PullRequest),
 		PRCommits:    make(map[int][]*github.RepositoryCommit),
 		CommitFiles:  make(map[string][]*github.CommitFile),
+		Files:        make(map[string][]string),
+		FileContents: make(map[string]map[string]map[string][]byte),
+		WorkflowRuns: make(map[string][]*github.WorkflowRun),
+		WorkflowJobs: make(map[int64][]*github.WorkflowJob),
 		BaseURL:      "fakeurl",
 	}
 }
@@ -65,6 +76,82 @@ func (fgc *FakeGithubClient) ListRepos(org string) ([]string, error) {
 	return fgc.Repos, nil
 }
 
+// ListRepositories lists repos under org, with their metadata
+func (fgc *FakeGithubClient) ListRepositories(org string) ([]*github.Repository, error) {
+	return fgc.Repositories, nil
+}
+
+// HasFile checks if a file exists at path on the default branch of repo
+func (fgc *FakeGithubClient) HasFile(org, repo, path string) (bool, error) {
+	for _, f := range fgc.Files[repo] {
+		if f == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DefaultBranch is the branch of files read without a ref
+const DefaultBranch = "main"
+
+// setFiles sets the contents of files on a branch of repo
+func (fgc *FakeGithubClient) setFiles(repo, branch string, files map[string][]byte) {
+	if _, ok := fgc.FileContents[repo]; !ok {
+		fgc.FileContents[repo] = make(map[string]map[string][]byte)
+	}
+	fgc.FileContents[repo][branch] = files
+}
+
+// GetFile gets the contents and the blob SHA of the file at path of repo on
+// ref, or on DefaultBranch if ref is empty. The SHA is faked from the contents
+func (fgc *FakeGithubClient) GetFile(org, repo, path, ref string) ([]byte, string, error) {
+	if ref == "" {
+		ref = DefaultBranch
+	}
+	contents, ok := fgc.FileContents[repo][ref][path]
+	if !ok {
+		return nil, "", nil
+	}
+	return contents, fakeSHA(contents), nil
+}
+
+// CreateBranch creates a branch in repo with the files of base, or resets it
+// to base if it already exists
+func (fgc *FakeGithubClient) CreateBranch(org, repo, branch, base string) error {
+	if fgc.Branches == nil {
+		fgc.Branches = make(map[string][]*github.Branch)
+	}
+	files := make(map[string][]byte)
+	for path, contents := range fgc.FileContents[repo][base] {
+		files[path] = contents
+	}
+	fgc.setFiles(repo, branch, files)
+	for _, b := range fgc.Branches[repo] {
+		if b.GetName() == branch {
+			return nil
+		}
+	}
+	fgc.Branches[repo] = append(fgc.Branches[repo], &github.Branch{Name: &branch})
+	return nil
+}
+
+// UpdateFile sets the contents of the file at path on a branch of repo,
+// failing if sha is not the one of the current contents
+func (fgc *FakeGithubClient) UpdateFile(org, repo, branch, path, message string, contents []byte, sha string) error {
+	if current, ok := fgc.FileContents[repo][branch][path]; ok != (sha != "") || (ok && fakeSHA(current) != sha) {
+		return fmt.Errorf("sha %q does not match file %q", sha, path)
+	}
+	if _, ok := fgc.FileContents[repo][branch]; !ok {
+		fgc.setFiles(repo, branch, make(map[string][]byte))
+	}
+	fgc.FileContents[repo][branch][path] = contents
+	return nil
+}
+
+func fakeSHA(contents []byte) string {
+	return fmt.Sprintf("%x", sha1.Sum(contents))
+}
+
 // ListIssuesByRepo lists issues within given repo, filters by labels if provided
 func (fgc *FakeGithubClient) ListIssuesByRepo(org, repo string, labels []string) ([]*github.Issue, error) {
 	var issues []*github.Issue
@@ -102,6 +189,7 @@ func (fgc *FakeGithubClient) CreateIssue(org, repo, title, body string) (*github
 		Number:        &issueNumber,
 		State:         &stateStr,
 		URL:           &url,
+		HTMLURL:       &url,
 		RepositoryURL: &repoURL,
 	}
 	if _, ok := fgc.Issues[repo]; !ok {
@@ -121,6 +209,17 @@ func (fgc *FakeGithubClient) ReopenIssue(org, repo string, issueNumber int) erro
 	return fgc.updateIssueState(org, repo, ghutil.IssueOpenState, issueNumber)
 }
 
+// EditIssue replaces the title and the body of issue
+func (fgc *FakeGithubClient) EditIssue(org, repo string, issueNumber int, title, body string) error {
+	targetIssue := fgc.Issues[repo][issueNumber]
+	if nil == targetIssue {
+		return fmt.Errorf("cannot find issue")
+	}
+	targetIssue.Title = &title
+	targetIssue.Body = &body
+	return nil
+}
+
 // ListComments gets all comments from issue
 func (fgc *FakeGithubClient) ListComments(org, repo string, issueNumber int) ([]*github.IssueComment, error) {
 	ghComments := fgc.Comments[issueNumber]
@@ -162,7 +261,9 @@ func (fgc *FakeGithubClient) EditComment(org, repo string, commentID int64, comm
 	if nil != err {
 		return err
 	}
+	updatedAt := time.Now()
 	comment.Body = &commentBody
+	comment.UpdatedAt = &updatedAt
 	return nil
 }
 
@@ -298,6 +399,17 @@ func (fgc *FakeGithubClient) EditPullRequest(org, repo string, ID int, title, bo
 	return PR, nil
 }
 
+// ClosePullRequest closes PullRequest
+func (fgc *FakeGithubClient) ClosePullRequest(org, repo string, ID int) error {
+	PR, err := fgc.GetPullRequest(org, repo, ID)
+	if nil != err {
+		return err
+	}
+	stateStr := string(ghutil.PullRequestCloseState)
+	PR.State = &stateStr
+	return nil
+}
+
 // EnsureLabelForPullRequest ensures the label exists for the PullRequest
 func (fgc *FakeGithubClient) EnsureLabelForPullRequest(org, repo string, ID int, label string) error {
 	PR, err := fgc.GetPullRequest(org, repo, ID)
@@ -362,11 +474,53 @@ func (fgc *FakeGithubClient) CreatePullRequest(org, repo, head, base, title, bod
 
 // ListBranches lists branchs for given repo
 func (fgc *FakeGithubClient) ListBranches(org, repo string) ([]*github.Branch, error) {
-	branches := make([]*github.Branch, 0, len(fgc.Branches))
-	for _, b := range fgc.Branches {
-		branches = append(branches, b...)
+	return fgc.Branches[repo], nil
+}
+
+// DispatchWorkflow creates a queued run of the workflow on ref
+func (fgc *FakeGithubClient) DispatchWorkflow(org, repo string, workflowID int64, ref string, inputs map[string]interface{}) error {
+	runID := int64(fgc.getNextNumber())
+	url := fmt.Sprintf("%s/%s/%s/actions/runs/%d", fgc.BaseURL, org, repo, runID)
+	fgc.WorkflowRuns[repo] = append(fgc.WorkflowRuns[repo], &github.WorkflowRun{
+		ID:         &runID,
+		WorkflowID: &workflowID,
+		HeadBranch: &ref,
+		Event:      github.String("workflow_dispatch"),
+		Status:     github.String("queued"),
+		HTMLURL:    &url,
+		CreatedAt:  &github.Timestamp{Time: time.Now()},
+	})
+	return nil
+}
+
+// ListRecentWorkflowRuns lists the runs of a workflow in repo, filters by
+// branch and event if provided
+func (fgc *FakeGithubClient) ListRecentWorkflowRuns(org, repo string, workflowID int64, branch, event string) ([]*github.WorkflowRun, error) {
+	var runs []*github.WorkflowRun
+	for _, run := range fgc.WorkflowRuns[repo] {
+		if run.GetWorkflowID() != workflowID ||
+			(branch != "" && run.GetHeadBranch() != branch) ||
+			(event != "" && run.GetEvent() != event) {
+			continue
+		}
+		runs = append(runs, run)
 	}
-	return branches, nil
+	return runs, nil
+}
+
+// GetWorkflowRun gets a workflow run by run ID
+func (fgc *FakeGithubClient) GetWorkflowRun(org, repo string, runID int64) (*github.WorkflowRun, error) {
+	for _, run := range fgc.WorkflowRuns[repo] {
+		if run.GetID() == runID {
+			return run, nil
+		}
+	}
+	return nil, fmt.Errorf("workflow run %d not exist", runID)
+}
+
+// ListWorkflowJobs lists the jobs of a workflow run
+func (fgc *FakeGithubClient) ListWorkflowJobs(org, repo string, runID int64) ([]*github.WorkflowJob, error) {
+	return fgc.WorkflowJobs[runID], nil
 }
 
 // AddFileToCommit adds file to commit
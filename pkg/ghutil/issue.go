@@ -98,6 +98,23 @@ func (gc *GithubClient) ReopenIssue(org, repo string, issueNumber int) error {
 	return gc.updateIssueState(org, repo, IssueOpenState, issueNumber)
 }
 
+// EditIssue replaces the title and the body of issue
+func (gc *GithubClient) EditIssue(org, repo string, issueNumber int, title, body string) error {
+	issueRequest := &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	}
+	_, err := gc.retry(
+		fmt.Sprintf("editing issue '%s %s %d'", org, repo, issueNumber),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			_, resp, err := gc.Client.Issues.Edit(ctx, org, repo, issueNumber, issueRequest)
+			return resp, err
+		},
+	)
+	return err
+}
+
 // ListComments gets all comments from issue
 func (gc *GithubClient) ListComments(org, repo string, issueNumber int) ([]*github.IssueComment, error) {
 	commentListOptions := &github.IssueListCommentsOptions{}
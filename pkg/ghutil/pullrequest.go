@@ -185,6 +185,20 @@ func (gc *GithubClient) EditPullRequest(org, repo string, ID int, title, body st
 	return res, err
 }
 
+// ClosePullRequest closes PullRequest
+func (gc *GithubClient) ClosePullRequest(org, repo string, ID int) error {
+	state := string(PullRequestCloseState)
+	_, err := gc.retry(
+		fmt.Sprintf("Close PullRequest '%d'", ID),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			_, resp, err := gc.Client.PullRequests.Edit(ctx, org, repo, ID, &github.PullRequest{State: &state})
+			return resp, err
+		},
+	)
+	return err
+}
+
 // CreatePullRequest creates PullRequest, passing head user and branch name "user:ref-name", and base branch name like "main"
 func (gc *GithubClient) CreatePullRequest(org, repo, head, base, title, body string) (*github.PullRequest, error) {
 	b := true
@@ -20,12 +20,23 @@ package ghutil
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/google/go-github/v32/github"
 )
 
 // ListRepos lists repos under org
 func (gc *GithubClient) ListRepos(org string) ([]string, error) {
+	repos, err := gc.ListRepositories(org)
+	res := make([]string, len(repos))
+	for i, repo := range repos {
+		res[i] = repo.GetName()
+	}
+	return res, err
+}
+
+// ListRepositories lists repos under org, with their metadata
+func (gc *GithubClient) ListRepositories(org string) ([]*github.Repository, error) {
 	repoListOptions := &github.RepositoryListOptions{}
 	genericList, err := gc.depaginate(
 		"listing repos",
@@ -42,13 +53,29 @@ func (gc *GithubClient) ListRepos(org string) ([]string, error) {
 			return interfaceList, resp, err
 		},
 	)
-	res := make([]string, len(genericList))
+	res := make([]*github.Repository, len(genericList))
 	for i, elem := range genericList {
-		res[i] = elem.(*github.Repository).GetName()
+		res[i] = elem.(*github.Repository)
 	}
 	return res, err
 }
 
+// HasFile checks if a file exists at path on the default branch of repo
+func (gc *GithubClient) HasFile(org, repo, path string) (bool, error) {
+	resp, err := gc.retry(
+		fmt.Sprintf("getting file %q from '%s %s'", path, org, repo),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			_, _, resp, err := gc.Client.Repositories.GetContents(ctx, org, repo, path, nil)
+			return resp, err
+		},
+	)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 // ListBranches lists branchs for given repo
 func (gc *GithubClient) ListBranches(org, repo string) ([]*github.Branch, error) {
 	branchListOptions := &github.BranchListOptions{}
@@ -73,3 +100,95 @@ func (gc *GithubClient) ListBranches(org, repo string) ([]*github.Branch, error)
 	}
 	return res, err
 }
+
+// GetFile gets the contents and the blob SHA of the file at path on ref of
+// repo, ref defaults to the default branch. Both are empty if the file doesn't
+// exist.
+func (gc *GithubClient) GetFile(org, repo, path, ref string) ([]byte, string, error) {
+	var file *github.RepositoryContent
+	resp, err := gc.retry(
+		fmt.Sprintf("getting file %q on %q from '%s %s'", path, ref, org, repo),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			var resp *github.Response
+			var err error
+			file, _, resp, err = gc.Client.Repositories.GetContents(ctx, org, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+			return resp, err
+		},
+	)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if file == nil {
+		return nil, "", fmt.Errorf("%q is not a file", path)
+	}
+	contents, err := file.GetContent()
+	return []byte(contents), file.GetSHA(), err
+}
+
+// CreateBranch creates a branch pointing to the head of base, or resets the
+// branch to it if it already exists
+func (gc *GithubClient) CreateBranch(org, repo, branch, base string) error {
+	var baseRef *github.Reference
+	if _, err := gc.retry(
+		fmt.Sprintf("getting branch %q from '%s %s'", base, org, repo),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			var resp *github.Response
+			var err error
+			baseRef, resp, err = gc.Client.Git.GetRef(ctx, org, repo, "refs/heads/"+base)
+			return resp, err
+		},
+	); err != nil {
+		return err
+	}
+	ref := &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: baseRef.Object.SHA},
+	}
+	resp, err := gc.retry(
+		fmt.Sprintf("creating branch %q in '%s %s'", branch, org, repo),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			_, resp, err := gc.Client.Git.CreateRef(ctx, org, repo, ref)
+			return resp, err
+		},
+	)
+	// The branch already exists
+	if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
+		_, err = gc.retry(
+			fmt.Sprintf("resetting branch %q to %q in '%s %s'", branch, base, org, repo),
+			maxRetryCount,
+			func() (*github.Response, error) {
+				_, resp, err := gc.Client.Git.UpdateRef(ctx, org, repo, ref, true)
+				return resp, err
+			},
+		)
+	}
+	return err
+}
+
+// UpdateFile commits contents to the file at path on branch of repo. sha is
+// the blob SHA of the file being replaced, or empty to create the file.
+func (gc *GithubClient) UpdateFile(org, repo, branch, path, message string, contents []byte, sha string) error {
+	opts := &github.RepositoryContentFileOptions{
+		Message: &message,
+		Content: contents,
+		Branch:  &branch,
+	}
+	if sha != "" {
+		opts.SHA = &sha
+	}
+	_, err := gc.retry(
+		fmt.Sprintf("updating file %q on %q in '%s %s'", path, branch, org, repo),
+		maxRetryCount,
+		func() (*github.Response, error) {
+			_, resp, err := gc.Client.Repositories.UpdateFile(ctx, org, repo, path, opts)
+			return resp, err
+		},
+	)
+	return err
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+var (
+	// relativeConstraint matches "N" or "N-<minors>".
+	relativeConstraint = regexp.MustCompile(`^[nN](?:-(\d+))?$`)
+	// versionPrefix matches the "v" prefix of versions in a range expression.
+	versionPrefix = regexp.MustCompile(`(^|[\s<>=!~^|])v(\d)`)
+)
+
+// Constraint selects release tags based on the module release, see
+// ParseConstraint.
+type Constraint struct {
+	expr string
+	// match reports if a tag version is in the constraint for the given
+	// module release.
+	match func(v, moduleRelease semver.Version) bool
+}
+
+// String returns the expression of the constraint.
+func (c *Constraint) String() string {
+	return c.expr
+}
+
+// Match reports if a release tag version is in the constraint for the module
+// release.
+func (c *Constraint) Match(v, moduleRelease semver.Version) bool {
+	return c.match(v, moduleRelease)
+}
+
+// ParseConstraint parses a semver constraint expression. With a constraint,
+// BestRefForRule only selects release tags, choosing the largest tag
+// matching the constraint. The expression is either:
+//
+//   - a semver range, e.g. ">=1.9.0 <1.11.0" or ">=1.9.0 <1.10.0 || >=1.11.0",
+//     see github.com/blang/semver/v4.ParseRange.
+//   - "N-<minors>", relative to the module release, e.g. "N-1" is the latest
+//     patch of the previous minor and "N" the latest patch of the same minor.
+func ParseConstraint(expr string) (*Constraint, error) {
+	expr = strings.TrimSpace(expr)
+	if m := relativeConstraint.FindStringSubmatch(expr); m != nil {
+		minors := uint64(0)
+		if m[1] != "" {
+			n, err := strconv.ParseUint(m[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", expr, err)
+			}
+			minors = n
+		}
+		return &Constraint{
+			expr: expr,
+			match: func(v, mr semver.Version) bool {
+				return mr.Minor >= minors && v.Major == mr.Major && v.Minor == mr.Minor-minors
+			},
+		}, nil
+	}
+
+	rng, err := semver.ParseRange(versionPrefix.ReplaceAllString(expr, "$1$2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint %q: %w", expr, err)
+	}
+	return &Constraint{
+		expr: expr,
+		match: func(v, _ semver.Version) bool {
+			return rng(v)
+		},
+	}, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+)
+
+func TestParseRule_Constraint(t *testing.T) {
+	tests := map[string]struct {
+		expr    string
+		wantErr bool
+	}{
+		"range":            {expr: ">=1.9.0 <1.11.0"},
+		"range with v":     {expr: ">=v1.9.0 <v1.11.0"},
+		"or range":         {expr: ">=0.1.0 <0.2.0 || >=1.0.0"},
+		"wildcard":         {expr: "1.9.x"},
+		"same minor":       {expr: "N"},
+		"previous minor":   {expr: "N-1"},
+		"lower case":       {expr: "n-2"},
+		"garbage":          {expr: "dasddasdsa", wantErr: true},
+		"short version":    {expr: ">=1.9", wantErr: true},
+		"empty":            {expr: "", wantErr: true},
+		"relative garbage": {expr: "N-x", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule, err := ParseRule(tt.expr)
+			if (tt.wantErr && err == nil) || (!tt.wantErr && err != nil) {
+				t.Fatalf("unexpected error state, want error == %t, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if rule.Ruleset != InvalidRule || rule.Constraint != nil {
+					t.Errorf("ParseRule() = %+v, want %v", rule, InvalidRule)
+				}
+				return
+			}
+			if rule.Constraint == nil {
+				t.Fatalf("ParseRule() = %+v, want a constraint", rule)
+			}
+			if got := rule.String(); got != tt.expr {
+				t.Errorf("String() = %q, want %q", got, tt.expr)
+			}
+			if got := Ruleset(tt.expr); got != InvalidRule {
+				t.Errorf("Ruleset() = %v, want %v", got, InvalidRule)
+			}
+		})
+	}
+}
+
+func TestParseRule_ConstraintError(t *testing.T) {
+	_, err := ParseRule(">=1.9 <")
+	if err == nil {
+		t.Fatal("ParseRule() = nil error, want an error")
+	}
+	if _, cerr := ParseConstraint(">=1.9 <"); !strings.HasSuffix(err.Error(), cerr.Error()) || errors.Unwrap(err) == nil {
+		t.Errorf("ParseRule() = %v, want it to wrap %v", err, cerr)
+	}
+}
+
+func TestParseRule_Ruleset(t *testing.T) {
+	for _, rt := range []RulesetType{AnyRule, ReleaseOrReleaseBranchRule, ReleaseRule, ReleaseBranchRule} {
+		rule, err := ParseRule(rt.String())
+		if err != nil || rule.Ruleset != rt || rule.Constraint != nil {
+			t.Errorf("ParseRule(%q) = %+v, %v, want %v", rt, rule, err, rt)
+		}
+	}
+}
+
+func TestRule_JSON(t *testing.T) {
+	for _, s := range []string{"Release", "N-1", ">=1.9.0 <1.11.0"} {
+		rule, err := ParseRule(s)
+		if err != nil {
+			t.Fatal("ParseRule() =", err)
+		}
+		b, err := json.Marshal(rule)
+		if err != nil {
+			t.Fatal("json.Marshal() =", err)
+		}
+		var text string
+		if err := json.Unmarshal(b, &text); err != nil || text != s {
+			t.Errorf("json.Marshal() = %s, want the string %q", b, s)
+		}
+		var got Rule
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal("json.Unmarshal() =", err)
+		}
+		if got.String() != s {
+			t.Errorf("json round trip of %s = %v", b, got)
+		}
+	}
+	var got Rule
+	if err := json.Unmarshal([]byte(`"Relase"`), &got); err == nil {
+		t.Error("json.Unmarshal() of an invalid rule = nil error, want an error")
+	}
+}
+
+func TestRepo_BestRefFor_Constraint(t *testing.T) {
+	repo := &Repo{
+		Ref:           "ref",
+		DefaultBranch: "main",
+		Tags:          []string{"v1.8.3", "v1.9.0", "v1.9.4", "v1.10.0", "v1.10.1", "v1.11.0-rc.1", "v1.11.0", "v2.0.0", "vfoo", "bar"},
+		Branches:      []string{"release-1.9", "release-1.10", "release-1.11", "main"},
+	}
+
+	tests := map[string]struct {
+		expr          string
+		moduleRelease semver.Version
+		want          string
+		refType       RefType
+	}{
+		"range": {
+			expr:          ">=1.9.0 <1.11.0",
+			moduleRelease: semver.MustParse("1.11.0"),
+			want:          "ref@v1.10.1",
+			refType:       ReleaseRef,
+		},
+		"range, previous minor only": {
+			expr:          ">=1.9.0 <1.10.0",
+			moduleRelease: semver.MustParse("1.11.0"),
+			want:          "ref@v1.9.4",
+			refType:       ReleaseRef,
+		},
+		"range, without v2 tags of a root module": {
+			expr:          ">=1.9.0",
+			moduleRelease: semver.MustParse("1.11.0"),
+			want:          "ref@v1.11.0",
+			refType:       ReleaseRef,
+		},
+		"range, no match": {
+			expr:          ">=2.0.0",
+			moduleRelease: semver.MustParse("1.11.0"),
+			want:          "ref",
+			refType:       NoRef,
+		},
+		"N": {
+			expr:          "N",
+			moduleRelease: semver.MustParse("1.10.0"),
+			want:          "ref@v1.10.1",
+			refType:       ReleaseRef,
+		},
+		"N-1": {
+			expr:          "N-1",
+			moduleRelease: semver.MustParse("1.11.0"),
+			want:          "ref@v1.10.1",
+			refType:       ReleaseRef,
+		},
+		"N-2": {
+			expr:          "N-2",
+			moduleRelease: semver.MustParse("1.11.0"),
+			want:          "ref@v1.9.4",
+			refType:       ReleaseRef,
+		},
+		"N-1, before the first minor": {
+			expr:          "N-1",
+			moduleRelease: semver.MustParse("2.0.0"),
+			want:          "ref",
+			refType:       NoRef,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := ParseConstraint(tt.expr)
+			if err != nil {
+				t.Fatal("ParseConstraint() =", err)
+			}
+			got, refType := repo.BestRefForRule(tt.moduleRelease, tt.moduleRelease, Rule{Constraint: c})
+			if got != tt.want || refType != tt.refType {
+				t.Errorf("BestRefFor() = %q, %v, want %q, %v", got, refType, tt.want, tt.refType)
+			}
+		})
+	}
+}
@@ -21,46 +21,30 @@ import (
 	"strings"
 
 	"github.com/blang/semver/v4"
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/config"
-	"github.com/go-git/go-git/v5/storage/memory"
 )
 
 // Repo is a simplified git remote, containing only the list of tags, default
 // branch and branches.
 type Repo struct {
-	Ref           string
-	DefaultBranch string
-	Tags          []string
-	Branches      []string
+	Ref           string   `json:"ref"`
+	DefaultBranch string   `json:"defaultBranch"`
+	Tags          []string `json:"tags"`
+	Branches      []string `json:"branches"`
+
+	// TagPrefix is the prefix of the release tags of a module in a
+	// subdirectory of the repo, e.g. "tools/foo/" for tags like
+	// "tools/foo/v1.2.3". Empty for a module at the root of the repo.
+	TagPrefix string `json:"tagPrefix,omitempty"`
+	// Major is the major version of the module path suffix, e.g. 2 for
+	// "example.com/foo/v2". Only release tags of that major version are
+	// selected. Zero for modules without a major version suffix.
+	Major uint64 `json:"major,omitempty"`
 }
 
-// GetRepo will fetch a git repo and process it into a Repo object.
+// GetRepo will fetch a git repo and process it into a Repo object. The repo
+// is fetched through the current RepoSource, see SetRepoSource.
 func GetRepo(ref, url string) (*Repo, error) {
-	repo := new(Repo)
-	repo.Ref = ref
-
-	rem := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
-		Name: "origin",
-		URLs: []string{url},
-	})
-
-	refs, err := rem.List(&git.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	for _, ref := range refs {
-		if ref.Name().IsTag() {
-			repo.Tags = append(repo.Tags, ref.Name().Short())
-		} else if ref.Name().IsBranch() {
-			repo.Branches = append(repo.Branches, ref.Name().Short())
-		} else if ref.Name() == "HEAD" { // Default branch.
-			repo.DefaultBranch = ref.Target().Short()
-		}
-	}
-
-	return repo, nil
+	return source.GetRepo(ref, url)
 }
 
 type RefType int
@@ -84,34 +68,57 @@ var refTypeString = []string{"Branch", "Default Branch", "Release Branch", "Rele
 
 // String returns the string of RefType in human readable form.
 func (rt RefType) String() string {
-	if rt >= DefaultBranchRef && rt <= NoRef {
+	if rt >= BranchRef && rt <= NoRef {
 		return refTypeString[rt]
 	}
 	return ""
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (rt RefType) MarshalText() ([]byte, error) {
+	return []byte(rt.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text, as written
+// by MarshalText for UndefinedRef, is UndefinedRef, any other unknown text
+// is an error.
+func (rt *RefType) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*rt = UndefinedRef
+		return nil
+	}
+	for i, s := range refTypeString {
+		if s == string(text) {
+			*rt = RefType(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown ref type %q, want one of %q", text, refTypeString)
+}
+
+// BestRefForRule is like BestRefFor with the ruleset of the rule. For rules
+// with a constraint, only release tags matching the constraint are selected.
+func (r *Repo) BestRefForRule(release, moduleRelease semver.Version, rule Rule) (string, RefType) {
+	if rule.Constraint == nil {
+		return r.BestRefFor(release, moduleRelease, rule.Ruleset)
+	}
+	if largest := r.largestTag(func(v semver.Version) bool {
+		return rule.Constraint.Match(v, moduleRelease)
+	}); largest != nil {
+		return fmt.Sprintf("%s@%s", r.Ref, ReleaseVersion(*largest)), ReleaseRef
+	}
+	return r.Ref, NoRef
+}
+
 // BestRefFor Returns module@ref, isRelease based on the provided ruleset for
 // a this release.
 func (r *Repo) BestRefFor(release, moduleRelease semver.Version, ruleset RulesetType) (string, RefType) {
 	switch ruleset {
 	case AnyRule, ReleaseOrReleaseBranchRule, ReleaseRule:
-		var largest *semver.Version
 		// Look for a release.
-		for _, t := range r.Tags {
-			if sv, ok := normalizeTagVersion(t); ok {
-				v, _ := semver.Make(sv)
-				// Go does not understand how to fetch semver tags with pre or build tags, skip those.
-				if v.Pre != nil || v.Build != nil {
-					continue
-				}
-				if v.Major == moduleRelease.Major && v.Minor == moduleRelease.Minor {
-					if largest == nil || largest.LT(v) {
-						largest = &v
-					}
-				}
-			}
-		}
-		if largest != nil {
+		if largest := r.largestTag(func(v semver.Version) bool {
+			return v.Major == moduleRelease.Major && v.Minor == moduleRelease.Minor
+		}); largest != nil {
 			return fmt.Sprintf("%s@%s", r.Ref, ReleaseVersion(*largest)), ReleaseRef
 		}
 	}
@@ -146,6 +153,51 @@ func (r *Repo) BestRefFor(release, moduleRelease semver.Version, ruleset Ruleset
 	return r.Ref, NoRef
 }
 
+// largestTag returns the largest release tag version accepted by match, or
+// nil if there is none.
+func (r *Repo) largestTag(match func(semver.Version) bool) *semver.Version {
+	var largest *semver.Version
+	for _, t := range r.Tags {
+		// Tags of a module in a subdirectory are prefixed with its path.
+		if !strings.HasPrefix(t, r.TagPrefix) {
+			continue
+		}
+		if sv, ok := normalizeTagVersion(t[len(r.TagPrefix):]); ok {
+			v, err := semver.Make(sv)
+			if err != nil {
+				continue
+			}
+			// Go does not understand how to fetch semver tags with pre or build tags, skip those.
+			if v.Pre != nil || v.Build != nil {
+				continue
+			}
+			if !r.AcceptsMajor(v.Major) {
+				continue
+			}
+			if match(v) && (largest == nil || largest.LT(v)) {
+				largest = &v
+			}
+		}
+	}
+	return largest
+}
+
+// AcceptsMajor reports if versions of a major version can be versions of the
+// module. Modules with a major version suffix only accept that major, and
+// modules without one only v0 and v1, as Go rejects v2 and above for them.
+func (r *Repo) AcceptsMajor(major uint64) bool {
+	if r.Major >= 2 {
+		return major == r.Major
+	}
+	return major < 2
+}
+
+// ReleaseTag returns the release tag to create for a given version of the
+// module, including the TagPrefix of modules in a subdirectory.
+func (r *Repo) ReleaseTag(v semver.Version) string {
+	return r.TagPrefix + ReleaseVersion(v)
+}
+
 func normalizeTagVersion(v string) (string, bool) {
 	if strings.HasPrefix(v, "v") {
 		// No need to account for unicode widths.
@@ -172,6 +224,29 @@ func ReleaseBranchVersion(v semver.Version) string {
 	return fmt.Sprintf("release-%d.%d", v.Major, v.Minor)
 }
 
+// LatestReleaseBranch returns the release branch with the highest version
+// among branches, or false if there is none.
+func LatestReleaseBranch(branches []string) (string, bool) {
+	var largest *semver.Version
+	for _, b := range branches {
+		bv, ok := normalizeBranchVersion(b)
+		if !ok {
+			continue
+		}
+		v, err := semver.Make(bv)
+		if err != nil {
+			continue
+		}
+		if largest == nil || largest.LT(v) {
+			largest = &v
+		}
+	}
+	if largest == nil {
+		return "", false
+	}
+	return ReleaseBranchVersion(*largest), true
+}
+
 // ParseRef takes a go module ref and converts it to the module name and RefType.
 // ParseRef expects ref to be in the form "module@ref".
 // Only release branches and
@@ -17,6 +17,8 @@ limitations under the License.
 package git
 
 import (
+	"encoding/json"
+	"strconv"
 	"testing"
 
 	"github.com/blang/semver/v4"
@@ -377,11 +379,50 @@ func TestBranchVersion(t *testing.T) {
 	}
 }
 
+func TestLatestReleaseBranch(t *testing.T) {
+	tests := map[string]struct {
+		branches []string
+		want     string
+		wantOK   bool
+	}{
+		"minor order": {
+			branches: []string{"main", "release-1.9", "release-1.10", "release-1.2"},
+			want:     "release-1.10",
+			wantOK:   true,
+		},
+		"major order": {
+			branches: []string{"release-0.26", "release-1.0"},
+			want:     "release-1.0",
+			wantOK:   true,
+		},
+		"ignores invalid": {
+			branches: []string{"release-next", "release-0.1"},
+			want:     "release-0.1",
+			wantOK:   true,
+		},
+		"no release branch": {
+			branches: []string{"main", "release-next"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := LatestReleaseBranch(tt.branches)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("LatestReleaseBranch() = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestRefType_String(t *testing.T) {
 	tests := map[string]struct {
 		rt   RefType
 		want string
 	}{
+		"BranchRef": {
+			rt:   BranchRef,
+			want: "Branch",
+		},
 		"DefaultBranchRef": {
 			rt:   DefaultBranchRef,
 			want: "Default Branch",
@@ -460,3 +501,132 @@ func TestParseRef(t *testing.T) {
 		})
 	}
 }
+
+func TestRefType_JSON(t *testing.T) {
+	for _, rt := range []RefType{BranchRef, DefaultBranchRef, ReleaseBranchRef, ReleaseRef, NoRef} {
+		b, err := json.Marshal(rt)
+		if err != nil {
+			t.Fatal("json.Marshal() =", err)
+		}
+		if want := strconv.Quote(rt.String()); string(b) != want {
+			t.Errorf("json.Marshal() = %s, want %s", b, want)
+		}
+		var got RefType
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal("json.Unmarshal() =", err)
+		}
+		if got != rt {
+			t.Errorf("json.Unmarshal() = %v, want %v", got, rt)
+		}
+	}
+}
+
+func TestRefType_UnmarshalText(t *testing.T) {
+	var got RefType
+	if err := got.UnmarshalText(nil); err != nil || got != UndefinedRef {
+		t.Errorf("UnmarshalText(\"\") = %v, %v, want %v", got, err, UndefinedRef)
+	}
+	for _, text := range []string{"Relase", "release", "Undefined"} {
+		if err := got.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = nil error, want an error", text)
+		}
+	}
+	var dep struct {
+		RefType RefType `json:"refType"`
+	}
+	if err := json.Unmarshal([]byte(`{"refType": "Branhc"}`), &dep); err == nil {
+		t.Error("json.Unmarshal() of an unknown ref type = nil error, want an error")
+	}
+}
+
+func TestRepo_BestRefFor_ModulePath(t *testing.T) {
+	tests := map[string]struct {
+		repo          *Repo
+		moduleRelease semver.Version
+		want          string
+		refType       RefType
+	}{
+		"subdirectory module": {
+			repo: &Repo{
+				Ref:       "knative.dev/hack/schema",
+				Tags:      []string{"v1.10.3", "schema/v1.10.0", "schema/v1.10.1", "other/v1.10.2"},
+				TagPrefix: "schema/",
+			},
+			moduleRelease: semver.MustParse("1.10.0"),
+			want:          "knative.dev/hack/schema@v1.10.1",
+			refType:       ReleaseRef,
+		},
+		"subdirectory module without tags": {
+			repo: &Repo{
+				Ref:       "knative.dev/hack/schema",
+				Tags:      []string{"v1.10.3"},
+				TagPrefix: "schema/",
+			},
+			moduleRelease: semver.MustParse("1.10.0"),
+			want:          "knative.dev/hack/schema",
+			refType:       NoRef,
+		},
+		"major version module": {
+			repo: &Repo{
+				Ref:   "github.com/example/lib/v2",
+				Tags:  []string{"v1.1.0", "v2.1.0", "v2.1.3", "v3.1.0"},
+				Major: 2,
+			},
+			moduleRelease: semver.MustParse("2.1.0"),
+			want:          "github.com/example/lib/v2@v2.1.3",
+			refType:       ReleaseRef,
+		},
+		"root module ignores v2 tags": {
+			repo: &Repo{
+				Ref:  "github.com/example/lib",
+				Tags: []string{"v1.1.0", "v1.1.2", "v2.1.0"},
+			},
+			moduleRelease: semver.MustParse("1.1.0"),
+			want:          "github.com/example/lib@v1.1.2",
+			refType:       ReleaseRef,
+		},
+		"major version module, other major": {
+			repo: &Repo{
+				Ref:   "github.com/example/lib/v2",
+				Tags:  []string{"v1.1.0", "v2.1.0", "v3.1.0"},
+				Major: 2,
+			},
+			moduleRelease: semver.MustParse("3.1.0"),
+			want:          "github.com/example/lib/v2",
+			refType:       NoRef,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, refType := tt.repo.BestRefFor(tt.moduleRelease, tt.moduleRelease, ReleaseRule)
+			if got != tt.want || refType != tt.refType {
+				t.Errorf("BestRefFor() = %q, %v, want %q, %v", got, refType, tt.want, tt.refType)
+			}
+		})
+	}
+}
+
+func TestRepo_ReleaseTag(t *testing.T) {
+	v := semver.MustParse("1.10.2")
+	if got, want := (&Repo{}).ReleaseTag(v), "v1.10.2"; got != want {
+		t.Errorf("ReleaseTag() = %q, want %q", got, want)
+	}
+	if got, want := (&Repo{TagPrefix: "schema/"}).ReleaseTag(v), "schema/v1.10.2"; got != want {
+		t.Errorf("ReleaseTag() = %q, want %q", got, want)
+	}
+}
+
+func TestRulesetType_UnmarshalText(t *testing.T) {
+	for _, rt := range []RulesetType{AnyRule, ReleaseOrReleaseBranchRule, ReleaseRule, ReleaseBranchRule, InvalidRule} {
+		var got RulesetType
+		if err := got.UnmarshalText([]byte(rt.String())); err != nil || got != rt {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", rt, got, err, rt)
+		}
+	}
+	for _, text := range []string{"", "Relase", ">=1.9.0"} {
+		var got RulesetType
+		if err := got.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = nil error, want an error", text)
+		}
+	}
+}
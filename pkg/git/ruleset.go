@@ -16,7 +16,10 @@ limitations under the License.
 
 package git
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // RulesetType defines the rules to use for calculating repo.BestRefFor.
 type RulesetType int
@@ -54,6 +57,22 @@ func (rt RulesetType) String() string {
 	return ""
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (rt RulesetType) MarshalText() ([]byte, error) {
+	return []byte(rt.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Unknown text is an
+// error, "Invalid", as written by MarshalText for InvalidRule, is InvalidRule.
+func (rt *RulesetType) UnmarshalText(text []byte) error {
+	r, found := rulesetLookup[strings.ToLower(string(text))]
+	if !found {
+		return fmt.Errorf("unknown ruleset %q, want one of %q", text, rulesetTypeString)
+	}
+	*rt = r
+	return nil
+}
+
 // Ruleset converts a rule string into a RulesetType.
 func Ruleset(rule string) RulesetType {
 	if r, found := rulesetLookup[strings.ToLower(rule)]; found {
@@ -72,3 +91,49 @@ func Rulesets() []string {
 		// Invalid is omitted.
 	}
 }
+
+// Rule is a ruleset along with its constraint, the rules to use for
+// calculating repo.BestRefForRule.
+type Rule struct {
+	Ruleset RulesetType
+	// Constraint selects release tags instead of Ruleset when set, see
+	// ParseConstraint.
+	Constraint *Constraint
+}
+
+// ParseRule converts a rule string into a Rule, the rule string is either one
+// of Rulesets or a constraint expression, see ParseConstraint.
+func ParseRule(rule string) (Rule, error) {
+	if r := Ruleset(rule); r != InvalidRule {
+		return Rule{Ruleset: r}, nil
+	}
+	c, err := ParseConstraint(rule)
+	if err != nil {
+		return Rule{Ruleset: InvalidRule}, fmt.Errorf("invalid ruleset %q, please select one of: [%s] or a semver constraint: %w",
+			rule, strings.Join(Rulesets(), ", "), err)
+	}
+	return Rule{Constraint: c}, nil
+}
+
+// String returns the string represented by the Rule.
+func (r Rule) String() string {
+	if r.Constraint != nil {
+		return r.Constraint.String()
+	}
+	return r.Ruleset.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Rule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Rule) UnmarshalText(text []byte) error {
+	rule, err := ParseRule(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rule
+	return nil
+}
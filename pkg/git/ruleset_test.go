@@ -17,6 +17,7 @@ limitations under the License.
 package git
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 )
@@ -140,3 +141,19 @@ func TestRulesets(t *testing.T) {
 		})
 	}
 }
+
+func TestRulesetType_JSON(t *testing.T) {
+	for _, rt := range []RulesetType{AnyRule, ReleaseOrReleaseBranchRule, ReleaseRule, ReleaseBranchRule} {
+		b, err := json.Marshal(rt)
+		if err != nil {
+			t.Fatal("json.Marshal() =", err)
+		}
+		var got RulesetType
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal("json.Unmarshal() =", err)
+		}
+		if got != rt {
+			t.Errorf("json round trip of %s = %v", b, got)
+		}
+	}
+}
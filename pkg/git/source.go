@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// ErrOffline is returned by offline sources for lookups they can not answer.
+var ErrOffline = errors.New("offline lookup not found in store")
+
+// RepoSource fetches the refs of a remote git repo.
+type RepoSource interface {
+	GetRepo(ref, url string) (*Repo, error)
+}
+
+// RepoSourceFunc adapts a function to a RepoSource.
+type RepoSourceFunc func(ref, url string) (*Repo, error)
+
+// GetRepo implements RepoSource.
+func (f RepoSourceFunc) GetRepo(ref, url string) (*Repo, error) {
+	return f(ref, url)
+}
+
+// RemoteSource lists the refs of the remote repo, like `git ls-remote`.
+var RemoteSource RepoSource = RepoSourceFunc(getRemoteRepo)
+
+// OfflineSource never reaches the network and fails every lookup with
+// ErrOffline. Use it with CachedSource to only answer from a Store.
+var OfflineSource RepoSource = RepoSourceFunc(func(_, url string) (*Repo, error) {
+	return nil, fmt.Errorf("git repo %s: %w", url, ErrOffline)
+})
+
+// source is the RepoSource used by GetRepo.
+var source = RemoteSource
+
+// SetRepoSource changes the RepoSource used by GetRepo, and returns the
+// previous one.
+func SetRepoSource(s RepoSource) RepoSource {
+	prev := source
+	source = s
+	return prev
+}
+
+// CachedSource returns a RepoSource that answers from store when possible,
+// and saves the repos fetched from src into store otherwise.
+func CachedSource(src RepoSource, store Store) RepoSource {
+	return RepoSourceFunc(func(ref, url string) (*Repo, error) {
+		key := "git:" + url
+		repo := new(Repo)
+		found, err := store.Load(key, repo)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			repo.Ref = ref
+			return repo, nil
+		}
+
+		repo, err = src.GetRepo(ref, url)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Save(key, repo); err != nil {
+			return nil, err
+		}
+		return repo, nil
+	})
+}
+
+func getRemoteRepo(ref, url string) (*Repo, error) {
+	repo := new(Repo)
+	repo.Ref = ref
+
+	rem := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+
+	refs, err := rem.List(&git.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			repo.Tags = append(repo.Tags, ref.Name().Short())
+		} else if ref.Name().IsBranch() {
+			repo.Branches = append(repo.Branches, ref.Name().Short())
+		} else if ref.Name() == "HEAD" { // Default branch.
+			repo.DefaultBranch = ref.Target().Short()
+		}
+	}
+
+	return repo, nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store persists the results of remote lookups by key, so they can be
+// answered again without reaching the network.
+type Store interface {
+	// Load decodes the value saved for key into v, and returns false if there
+	// is no usable value for key.
+	Load(key string, v interface{}) (bool, error)
+	// Save saves v for key.
+	Save(key string, v interface{}) error
+}
+
+// dirEntry is the content of a DirStore file.
+type dirEntry struct {
+	Key   string          `json:"key"`
+	Time  time.Time       `json:"time"`
+	Value json.RawMessage `json:"value"`
+}
+
+// DirStore is a Store that keeps one file per key in a directory. Values
+// older than the TTL are ignored, a TTL of zero never expires values.
+type DirStore struct {
+	Dir string
+	TTL time.Duration
+
+	// now is used to check expiration, overridden by tests.
+	now func() time.Time
+}
+
+var _ Store = (*DirStore)(nil)
+
+// NewDirStore creates a DirStore for dir, creating the directory if needed.
+func NewDirStore(dir string, ttl time.Duration) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DirStore{Dir: dir, TTL: ttl}, nil
+}
+
+func (s *DirStore) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+func (s *DirStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Load implements Store.
+func (s *DirStore) Load(key string, v interface{}) (bool, error) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	entry := dirEntry{}
+	if err := json.Unmarshal(b, &entry); err != nil {
+		// A corrupted entry is a miss, it will be overwritten.
+		return false, nil
+	}
+	if entry.Key != key {
+		return false, nil
+	}
+	if s.TTL > 0 && s.clock().Sub(entry.Time) > s.TTL {
+		return false, nil
+	}
+	return true, json.Unmarshal(entry.Value, v)
+}
+
+// Save implements Store.
+func (s *DirStore) Save(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(dirEntry{Key: key, Time: s.clock(), Value: value})
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so concurrent runs never read a
+	// partial entry.
+	tmp, err := ioutil.TempFile(s.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// snapshot is the content of a SnapshotStore file.
+type snapshot struct {
+	Entries map[string]json.RawMessage `json:"entries"`
+}
+
+// SnapshotStore is a Store backed by a single JSON file that holds every
+// value. It never expires values, so it can be recorded once and used as a
+// fixture for offline runs and tests.
+type SnapshotStore struct {
+	// Path is the snapshot file.
+	Path string
+	// ReadOnly stores never write the snapshot file back, Save only keeps
+	// the value in memory.
+	ReadOnly bool
+
+	mu      sync.Mutex
+	entries map[string]json.RawMessage
+}
+
+var _ Store = (*SnapshotStore)(nil)
+
+// NewSnapshotStore loads the snapshot file at path. A missing file is an
+// empty snapshot, unless readOnly is set.
+func NewSnapshotStore(path string, readOnly bool) (*SnapshotStore, error) {
+	s := &SnapshotStore{
+		Path:     path,
+		ReadOnly: readOnly,
+		entries:  make(map[string]json.RawMessage),
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !readOnly {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snap := snapshot{}
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return nil, err
+	}
+	if snap.Entries != nil {
+		s.entries = snap.Entries
+	}
+	return s, nil
+}
+
+// Keys returns the sorted keys saved in the snapshot.
+func (s *SnapshotStore) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.entries))
+	for k := range s.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Load implements Store.
+func (s *SnapshotStore) Load(key string, v interface{}) (bool, error) {
+	s.mu.Lock()
+	value, found := s.entries[key]
+	s.mu.Unlock()
+
+	if !found {
+		return false, nil
+	}
+	return true, json.Unmarshal(value, v)
+}
+
+// Save implements Store. The whole snapshot file is written on every call so
+// that the recording survives commands exiting early.
+func (s *SnapshotStore) Save(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = value
+	if s.ReadOnly {
+		return nil
+	}
+	b, err := json.MarshalIndent(snapshot{Entries: s.entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path, b, 0644)
+}
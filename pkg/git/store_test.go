@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDirStore(t *testing.T) {
+	store, err := NewDirStore(filepath.Join(t.TempDir(), "cache"), time.Hour)
+	if err != nil {
+		t.Fatal("NewDirStore() =", err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	got := &Repo{}
+	if found, err := store.Load("git:foo", got); err != nil || found {
+		t.Fatalf("Load() = %t, %v, want false, nil", found, err)
+	}
+
+	want := &Repo{Ref: "foo", DefaultBranch: "main", Tags: []string{"v0.1.0"}}
+	if err := store.Save("git:foo", want); err != nil {
+		t.Fatal("Save() =", err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if found, err := store.Load("git:foo", got); err != nil || !found {
+		t.Fatalf("Load() = %t, %v, want true, nil", found, err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Load() diff(-want,+got):\n", diff)
+	}
+
+	now = now.Add(time.Hour)
+	if found, err := store.Load("git:foo", got); err != nil || found {
+		t.Fatalf("Load() after TTL = %t, %v, want false, nil", found, err)
+	}
+}
+
+func TestSnapshotStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if _, err := NewSnapshotStore(path, true); err == nil {
+		t.Error("NewSnapshotStore() of a missing read only snapshot should fail")
+	}
+
+	store, err := NewSnapshotStore(path, false)
+	if err != nil {
+		t.Fatal("NewSnapshotStore() =", err)
+	}
+	want := &Repo{Ref: "foo", DefaultBranch: "main", Branches: []string{"main"}}
+	if err := store.Save("git:foo", want); err != nil {
+		t.Fatal("Save() =", err)
+	}
+
+	// Reload the recorded snapshot read only.
+	store, err = NewSnapshotStore(path, true)
+	if err != nil {
+		t.Fatal("NewSnapshotStore() =", err)
+	}
+	if diff := cmp.Diff([]string{"git:foo"}, store.Keys()); diff != "" {
+		t.Error("Keys() diff(-want,+got):\n", diff)
+	}
+	got := &Repo{}
+	if found, err := store.Load("git:foo", got); err != nil || !found {
+		t.Fatalf("Load() = %t, %v, want true, nil", found, err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Load() diff(-want,+got):\n", diff)
+	}
+}
+
+func TestCachedSource(t *testing.T) {
+	store, err := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshot.json"), false)
+	if err != nil {
+		t.Fatal("NewSnapshotStore() =", err)
+	}
+
+	calls := 0
+	src := RepoSourceFunc(func(ref, url string) (*Repo, error) {
+		calls++
+		return &Repo{Ref: ref, DefaultBranch: "main"}, nil
+	})
+	cached := CachedSource(src, store)
+	for _, ref := range []string{"foo", "bar"} {
+		repo, err := cached.GetRepo(ref, "https://example.com/repo")
+		if err != nil {
+			t.Fatal("GetRepo() =", err)
+		}
+		if repo.Ref != ref {
+			t.Errorf("repo.Ref = %q, want %q", repo.Ref, ref)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("source called %d times, want 1", calls)
+	}
+
+	offline := CachedSource(OfflineSource, store)
+	if _, err := offline.GetRepo("foo", "https://example.com/repo"); err != nil {
+		t.Error("GetRepo() of a stored repo =", err)
+	}
+	if _, err := offline.GetRepo("foo", "https://example.com/other"); !errors.Is(err, ErrOffline) {
+		t.Errorf("GetRepo() = %v, want %v", err, ErrOffline)
+	}
+}
@@ -20,17 +20,21 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"knative.dev/test-infra/pkg/git"
 
+	gomodule "golang.org/x/mod/module"
 	"golang.org/x/net/html"
 )
 
 // MetaImport represents the parsed <meta name="go-import"
 // content="prefix vcs reporoot" /> tags from HTML files.
 type MetaImport struct {
-	Prefix, VCS, RepoRoot string
+	Prefix   string `json:"prefix"`
+	VCS      string `json:"vcs"`
+	RepoRoot string `json:"repoRoot"`
 }
 
 func (m *MetaImport) OrgRepo() (string, string) {
@@ -71,9 +75,84 @@ func metaContent(doc *html.Node, name string) (string, error) {
 	return "", fmt.Errorf("missing <meta name=%s> in the node tree", name)
 }
 
+// MetaImportSource fetches the go-import meta tags for a url.
+type MetaImportSource interface {
+	GetMetaImport(url string) (*MetaImport, error)
+}
+
+// MetaImportSourceFunc adapts a function to a MetaImportSource.
+type MetaImportSourceFunc func(url string) (*MetaImport, error)
+
+// GetMetaImport implements MetaImportSource.
+func (f MetaImportSourceFunc) GetMetaImport(url string) (*MetaImport, error) {
+	return f(url)
+}
+
+// RemoteSource fetches the go-import meta tags over HTTP.
+var RemoteSource MetaImportSource = MetaImportSourceFunc(getRemoteMetaImport)
+
+// OfflineSource never reaches the network and fails every lookup with
+// git.ErrOffline. Use it with CachedSource to only answer from a git.Store.
+var OfflineSource MetaImportSource = MetaImportSourceFunc(func(url string) (*MetaImport, error) {
+	return nil, fmt.Errorf("go-import %s: %w", url, git.ErrOffline)
+})
+
+// source is the MetaImportSource used by GetMetaImport.
+var source = RemoteSource
+
+// SetMetaImportSource changes the MetaImportSource used by GetMetaImport, and
+// returns the previous one.
+func SetMetaImportSource(s MetaImportSource) MetaImportSource {
+	prev := source
+	source = s
+	return prev
+}
+
+// CachedSource returns a MetaImportSource that answers from store when
+// possible, and saves the meta imports fetched from src into store otherwise.
+func CachedSource(src MetaImportSource, store git.Store) MetaImportSource {
+	return MetaImportSourceFunc(func(url string) (*MetaImport, error) {
+		key := "go-import:" + url
+		meta := new(MetaImport)
+		found, err := store.Load(key, meta)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return meta, nil
+		}
+
+		meta, err = src.GetMetaImport(url)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Save(key, meta); err != nil {
+			return nil, err
+		}
+		return meta, nil
+	})
+}
+
+// UseStore makes GetMetaImport and git.GetRepo answer from store, saving the
+// results of remote lookups into it. If offline is set, lookups missing from
+// store fail with git.ErrOffline instead of reaching the network.
+func UseStore(store git.Store, offline bool) {
+	metaSrc, repoSrc := RemoteSource, git.RemoteSource
+	if offline {
+		metaSrc, repoSrc = OfflineSource, git.OfflineSource
+	}
+	SetMetaImportSource(CachedSource(metaSrc, store))
+	git.SetRepoSource(git.CachedSource(repoSrc, store))
+}
+
 // GetMetaImport fetches and parses header tags named go-import into a
-// MetaImport object.
+// MetaImport object. The meta import is fetched through the current
+// MetaImportSource, see SetMetaImportSource.
 func GetMetaImport(url string) (*MetaImport, error) {
+	return source.GetMetaImport(url)
+}
+
+func getRemoteMetaImport(url string) (*MetaImport, error) {
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, err
@@ -109,5 +188,34 @@ func ModuleToRepo(module string) (*git.Repo, error) {
 		return nil, errors.New("unknown VCS: " + meta.VCS)
 	}
 
-	return git.GetRepo(module, meta.RepoRoot)
+	repo, err := git.GetRepo(module, meta.RepoRoot)
+	if err != nil {
+		return nil, err
+	}
+	repo.TagPrefix, repo.Major = modulePathTags(module, meta.Prefix)
+	return repo, nil
+}
+
+// modulePathTags returns the release tag prefix and the major version of a
+// module, based on its path within the repo found at the go-import prefix.
+// For example, the module "knative.dev/foo/tools/bar/v2" of the repo at
+// "knative.dev/foo" is released with tags like "tools/bar/v2.1.0".
+func modulePathTags(module, prefix string) (string, uint64) {
+	path, pathMajor, ok := gomodule.SplitPathVersion(module)
+	if !ok {
+		path, pathMajor = module, ""
+	}
+
+	var major uint64
+	if strings.HasPrefix(pathMajor, "/v") {
+		major, _ = strconv.ParseUint(pathMajor[len("/v"):], 10, 64)
+	}
+
+	if !strings.HasPrefix(path, prefix) {
+		return "", major
+	}
+	if dir := strings.Trim(path[len(prefix):], "/"); dir != "" {
+		return dir + "/", major
+	}
+	return "", major
 }
@@ -191,3 +191,52 @@ func TestGetMetaImport_MissingGoImport(t *testing.T) {
 		t.Errorf("expected error, but did not get it.")
 	}
 }
+
+func TestModulePathTags(t *testing.T) {
+	tests := map[string]struct {
+		module        string
+		prefix        string
+		wantTagPrefix string
+		wantMajor     uint64
+	}{
+		"root": {
+			module: "knative.dev/pkg",
+			prefix: "knative.dev/pkg",
+		},
+		"subdirectory": {
+			module:        "knative.dev/test-infra/tools/foo",
+			prefix:        "knative.dev/test-infra",
+			wantTagPrefix: "tools/foo/",
+		},
+		"major": {
+			module:    "github.com/example/lib/v2",
+			prefix:    "github.com/example/lib",
+			wantMajor: 2,
+		},
+		"subdirectory major": {
+			module:        "knative.dev/test-infra/tools/foo/v3",
+			prefix:        "knative.dev/test-infra",
+			wantTagPrefix: "tools/foo/",
+			wantMajor:     3,
+		},
+		"gopkg.in": {
+			module: "gopkg.in/yaml.v2",
+			prefix: "gopkg.in/yaml.v2",
+		},
+		"unrelated prefix": {
+			module: "knative.dev/pkg",
+			prefix: "example.com/pkg",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tagPrefix, major := modulePathTags(tt.module, tt.prefix)
+			if tagPrefix != tt.wantTagPrefix {
+				t.Errorf("modulePathTags() tag prefix = %q, want %q", tagPrefix, tt.wantTagPrefix)
+			}
+			if major != tt.wantMajor {
+				t.Errorf("modulePathTags() major = %d, want %d", major, tt.wantMajor)
+			}
+		})
+	}
+}
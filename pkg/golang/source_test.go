@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package golang
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"knative.dev/test-infra/pkg/git"
+)
+
+func useSnapshot(t *testing.T, path string) {
+	t.Helper()
+	store, err := git.NewSnapshotStore(path, true)
+	if err != nil {
+		t.Fatal("NewSnapshotStore() =", err)
+	}
+	prevMeta := SetMetaImportSource(nil)
+	prevRepo := git.SetRepoSource(nil)
+	t.Cleanup(func() {
+		SetMetaImportSource(prevMeta)
+		git.SetRepoSource(prevRepo)
+	})
+	UseStore(store, true)
+}
+
+func TestModuleToRepo_Offline(t *testing.T) {
+	useSnapshot(t, "testdata/snapshot.json")
+
+	repo, err := ModuleToRepo("knative.dev/pkg")
+	if err != nil {
+		t.Fatal("ModuleToRepo() =", err)
+	}
+	want := &git.Repo{
+		Ref:           "knative.dev/pkg",
+		DefaultBranch: "main",
+		Tags:          []string{"knative-v1.9.0", "knative-v1.9.1", "knative-v1.10.0", "v0.27.0"},
+		Branches:      []string{"main", "release-1.9", "release-1.10"},
+	}
+	if diff := cmp.Diff(want, repo); diff != "" {
+		t.Error("ModuleToRepo() diff(-want,+got):\n", diff)
+	}
+
+	if _, err := ModuleToRepo("knative.dev/serving"); !errors.Is(err, git.ErrOffline) {
+		t.Errorf("ModuleToRepo() = %v, want %v", err, git.ErrOffline)
+	}
+}
+
+func TestCachedSource(t *testing.T) {
+	store, err := git.NewSnapshotStore(t.TempDir()+"/snapshot.json", false)
+	if err != nil {
+		t.Fatal("NewSnapshotStore() =", err)
+	}
+
+	calls := 0
+	src := MetaImportSourceFunc(func(url string) (*MetaImport, error) {
+		calls++
+		return &MetaImport{Prefix: "knative.dev/pkg", VCS: "git", RepoRoot: "https://github.com/knative/pkg"}, nil
+	})
+	cached := CachedSource(src, store)
+	for i := 0; i < 3; i++ {
+		meta, err := cached.GetMetaImport("https://knative.dev/pkg?go-get=1")
+		if err != nil {
+			t.Fatal("GetMetaImport() =", err)
+		}
+		if want := "https://github.com/knative/pkg"; meta.RepoRoot != want {
+			t.Errorf("meta.RepoRoot got = %v, want %v", meta.RepoRoot, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("source called %d times, want 1", calls)
+	}
+}
+
+func TestModuleToRepo_ModulePath(t *testing.T) {
+	useSnapshot(t, "testdata/snapshot.json")
+
+	tests := map[string]struct {
+		module        string
+		wantTagPrefix string
+		wantMajor     uint64
+	}{
+		"root module": {
+			module: "knative.dev/pkg",
+		},
+		"subdirectory module": {
+			module:        "knative.dev/hack/schema",
+			wantTagPrefix: "schema/",
+		},
+		"major version module": {
+			module:    "github.com/example/lib/v2",
+			wantMajor: 2,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, err := ModuleToRepo(tt.module)
+			if err != nil {
+				t.Fatal("ModuleToRepo() =", err)
+			}
+			if repo.TagPrefix != tt.wantTagPrefix {
+				t.Errorf("repo.TagPrefix = %q, want %q", repo.TagPrefix, tt.wantTagPrefix)
+			}
+			if repo.Major != tt.wantMajor {
+				t.Errorf("repo.Major = %d, want %d", repo.Major, tt.wantMajor)
+			}
+		})
+	}
+}
@@ -27,67 +27,127 @@ import (
 	"knative.dev/test-infra/pkg/golang"
 )
 
+// Dependency holds the ref selected for a dependency based on a ruleset.
+type Dependency struct {
+	// Module is the name of the dependency.
+	Module string `json:"module"`
+	// Ref is the selected ref in the form "module@ref", or the module name if
+	// no ref was found.
+	Ref     string      `json:"ref"`
+	RefType git.RefType `json:"refType"`
+	Ruleset git.Rule    `json:"ruleset"`
+}
+
+// CheckResult holds the refs selected for the dependencies of a module.
+type CheckResult struct {
+	Module string `json:"module"`
+	// Ready is true if a ref was found for every dependency, and go.sum and
+	// vendor/modules.txt match go.mod when verified.
+	Ready        bool         `json:"ready"`
+	Dependencies []Dependency `json:"dependencies"`
+	// Mismatches are the go.sum and vendor/modules.txt entries that do not
+	// match go.mod, only set by Verify.
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+}
+
 // Check examines a go mod file for dependencies and  determines if each have a release artifact
 // based on the ruleset provided. Check leverages the same rules used by
-// knative.dev/test-infra/pkg/git.Repo().BestRefFor
-func Check(gomod, release, moduleRelease string, selector Matcher, ruleset git.RulesetType, out io.Writer) error {
-	modulePkgs, _, err := Modules([]string{gomod}, selector)
+// knative.dev/test-infra/pkg/git.Repo().BestRefForRule
+func Check(gomod, release, moduleRelease string, selector Matcher, rule git.Rule, out io.Writer) error {
+	results, err := CheckResults(gomod, release, moduleRelease, selector, rule)
 	if err != nil {
 		return err
 	}
 
-	for module, packages := range modulePkgs {
-		if err := check(module, packages, release, moduleRelease, ruleset, out); err != nil {
-			return err
+	for _, result := range results {
+		if out != nil {
+			_, _ = fmt.Fprintln(out, result.Module)
+		}
+
+		nonReady := make([]string, 0)
+		for _, dep := range result.Dependencies {
+			switch dep.RefType {
+			case git.NoRef:
+				nonReady = append(nonReady, dep.Ref)
+				if out != nil {
+					_, _ = fmt.Fprintln(out, "✘ ", dep.Ref)
+				}
+			default:
+				if out != nil {
+					_, _ = fmt.Fprintln(out, "✔ ", dep.Ref)
+				}
+			}
+		}
+
+		if len(nonReady) > 0 {
+			return &Error{
+				Module:       result.Module,
+				Dependencies: nonReady,
+			}
 		}
 	}
 	return nil
 }
 
-func check(module string, packages []string, release, moduleRelease string, ruleset git.RulesetType, out io.Writer) error {
-	r, err := semver.ParseTolerant(release)
+// CheckResults selects the refs of the dependencies of a go mod file based
+// on the ruleset provided, like Check, and returns them instead of writing
+// them.
+func CheckResults(gomod, release, moduleRelease string, selector Matcher, rule git.Rule) ([]*CheckResult, error) {
+	modulePkgs, _, err := Modules([]string{gomod}, selector)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	mr, err := semver.ParseTolerant(moduleRelease)
+	results := make([]*CheckResult, 0, len(modulePkgs))
+	for module, packages := range modulePkgs {
+		deps, err := selectRefs(packages, release, moduleRelease, rule)
+		if err != nil {
+			return nil, err
+		}
+		result := &CheckResult{
+			Module:       module,
+			Ready:        true,
+			Dependencies: deps,
+		}
+		for _, dep := range deps {
+			if dep.RefType == git.NoRef {
+				result.Ready = false
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// selectRefs selects the best ref of each package for the release based on
+// the ruleset.
+func selectRefs(packages []string, release, moduleRelease string, rule git.Rule) ([]Dependency, error) {
+	r, err := semver.ParseTolerant(release)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if out != nil {
-		_, _ = fmt.Fprintln(out, module)
+	mr, err := semver.ParseTolerant(moduleRelease)
+	if err != nil {
+		return nil, err
 	}
 
-	nonReady := make([]string, 0)
+	deps := make([]Dependency, 0, len(packages))
 	for _, pkg := range packages {
 		repo, err := golang.ModuleToRepo(pkg)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		ref, refType := repo.BestRefFor(r, mr, ruleset)
-		switch refType {
-		case git.NoRef:
-			nonReady = append(nonReady, ref)
-			if out != nil {
-				_, _ = fmt.Fprintln(out, "✘ ", ref)
-			}
-		default:
-			if out != nil {
-				_, _ = fmt.Fprintln(out, "✔ ", ref)
-			}
-		}
+		ref, refType := repo.BestRefForRule(r, mr, rule)
+		deps = append(deps, Dependency{
+			Module:  pkg,
+			Ref:     ref,
+			RefType: refType,
+			Ruleset: rule,
+		})
 	}
-
-	if len(nonReady) > 0 {
-		return &Error{
-			Module:       module,
-			Dependencies: nonReady,
-		}
-	}
-
-	return nil
+	return deps, nil
 }
 
 // DependencyErr is a Dependency Error instance. For use with with error.Is.
@@ -21,8 +21,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
 	"knative.dev/test-infra/pkg/git"
+	"knative.dev/test-infra/pkg/golang"
 )
 
 // TestCheck - This is an integration test, it will make a call out to the internet.
@@ -115,7 +117,7 @@ func TestCheck(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			selector, err := DefaultSelector(tt.domain)
 			require.NoError(t, err)
-			err = Check(tt.gomod, tt.release, tt.moduleRelease, selector, tt.rule, os.Stdout)
+			err = Check(tt.gomod, tt.release, tt.moduleRelease, selector, git.Rule{Ruleset: tt.rule}, os.Stdout)
 			if (tt.wantErr && err == nil) || (!tt.wantErr && err != nil) {
 				t.Errorf("unexpected error state, want error == %t, got %v", tt.wantErr, err)
 			}
@@ -178,3 +180,46 @@ func TestError_Error(t *testing.T) {
 		})
 	}
 }
+
+// useSnapshot answers the git and go-import lookups of the test from the
+// recorded snapshot, without reaching the network.
+func useSnapshot(t *testing.T) {
+	t.Helper()
+	store, err := git.NewSnapshotStore("testdata/snapshot.json", true)
+	require.NoError(t, err)
+	prevMeta := golang.SetMetaImportSource(nil)
+	prevRepo := git.SetRepoSource(nil)
+	t.Cleanup(func() {
+		golang.SetMetaImportSource(prevMeta)
+		git.SetRepoSource(prevRepo)
+	})
+	golang.UseStore(store, true)
+}
+
+func TestCheckResults(t *testing.T) {
+	useSnapshot(t)
+	selector, err := DefaultSelector("knative.dev")
+	require.NoError(t, err)
+
+	got, err := CheckResults("testdata/gomod.float1", "v0.37", "v0.37", selector, git.Rule{Ruleset: git.ReleaseRule})
+	require.NoError(t, err)
+
+	want := []*CheckResult{{
+		Module: "knative.dev/test-demo1",
+		Ready:  false,
+		Dependencies: []Dependency{{
+			Module:  "knative.dev/eventing",
+			Ref:     "knative.dev/eventing@v0.37.1",
+			RefType: git.ReleaseRef,
+			Ruleset: git.Rule{Ruleset: git.ReleaseRule},
+		}, {
+			Module:  "knative.dev/pkg",
+			Ref:     "knative.dev/pkg",
+			RefType: git.NoRef,
+			Ruleset: git.Rule{Ruleset: git.ReleaseRule},
+		}},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("CheckResults() diff(-want,+got):\n", diff)
+	}
+}
@@ -16,44 +16,34 @@ limitations under the License.
 
 package gomod
 
-import (
-	"github.com/blang/semver/v4"
-
-	"knative.dev/test-infra/pkg/git"
-	"knative.dev/test-infra/pkg/golang"
-)
+import "knative.dev/test-infra/pkg/git"
 
 // Float examines a go mod file for dependencies and then discovers the best
 // go mod refs to use for a given release based on the provided ruleset.
 // Returns the set of module refs that were found. If no ref is found for a
 // dependency, Float omits that ref from the returned list. Float leverages
-// the same rules used by knative.dev/test-infra/pkg/git.Repo().BestRefFor
-func Float(gomod, release, moduleRelease string, selector Matcher, ruleset git.RulesetType) ([]string, error) {
-	_, packages, err := Modules([]string{gomod}, selector)
+// the same rules used by knative.dev/test-infra/pkg/git.Repo().BestRefForRule
+func Float(gomod, release, moduleRelease string, selector Matcher, rule git.Rule) ([]string, error) {
+	deps, err := FloatDependencies(gomod, release, moduleRelease, selector, rule)
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := semver.ParseTolerant(release)
-	if err != nil {
-		return nil, err
+	refs := make([]string, 0)
+	for _, dep := range deps {
+		if dep.RefType != git.NoRef {
+			refs = append(refs, dep.Ref)
+		}
 	}
+	return refs, nil
+}
 
-	mr, err := semver.ParseTolerant(moduleRelease)
+// FloatDependencies is like Float, but returns the selection for every
+// dependency, including the ones without a ref.
+func FloatDependencies(gomod, release, moduleRelease string, selector Matcher, rule git.Rule) ([]Dependency, error) {
+	_, packages, err := Modules([]string{gomod}, selector)
 	if err != nil {
 		return nil, err
 	}
-
-	refs := make([]string, 0)
-	for _, pkg := range packages {
-		repo, err := golang.ModuleToRepo(pkg)
-		if err != nil {
-			return nil, err
-		}
-
-		if ref, refType := repo.BestRefFor(r, mr, ruleset); refType != git.NoRef {
-			refs = append(refs, ref)
-		}
-	}
-	return refs, nil
+	return selectRefs(packages, release, moduleRelease, rule)
 }
@@ -19,6 +19,7 @@ package gomod
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
 	"knative.dev/test-infra/pkg/git"
 )
@@ -141,7 +142,7 @@ func TestFloat(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			selector, err := DefaultSelector(tt.domain)
 			require.NoError(t, err)
-			deps, err := Float(tt.gomod, tt.release, tt.moduleRelease, selector, tt.rule)
+			deps, err := Float(tt.gomod, tt.release, tt.moduleRelease, selector, git.Rule{Ruleset: tt.rule})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -196,10 +197,63 @@ func TestFloatUnhappy(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			selector, err := DefaultSelector(tt.domain)
 			require.NoError(t, err)
-			_, err = Float(tt.gomod, tt.release, tt.release, selector, tt.rule)
+			_, err = Float(tt.gomod, tt.release, tt.release, selector, git.Rule{Ruleset: tt.rule})
 			if err == nil {
 				t.Error("Expected an error")
 			}
 		})
 	}
 }
+
+func TestFloatDependencies(t *testing.T) {
+	useSnapshot(t)
+	selector, err := DefaultSelector("knative.dev")
+	require.NoError(t, err)
+
+	got, err := FloatDependencies("testdata/gomod.float1", "v1.10", "v1.10", selector, git.Rule{Ruleset: git.AnyRule})
+	require.NoError(t, err)
+
+	want := []Dependency{{
+		Module:  "knative.dev/eventing",
+		Ref:     "knative.dev/eventing@release-1.10",
+		RefType: git.ReleaseBranchRef,
+		Ruleset: git.Rule{Ruleset: git.AnyRule},
+	}, {
+		Module:  "knative.dev/pkg",
+		Ref:     "knative.dev/pkg@release-1.10",
+		RefType: git.ReleaseBranchRef,
+		Ruleset: git.Rule{Ruleset: git.AnyRule},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("FloatDependencies() diff(-want,+got):\n", diff)
+	}
+}
+
+func TestFloatDependencies_ModulePath(t *testing.T) {
+	useSnapshot(t)
+	selector := func(string) bool { return true }
+
+	got, err := FloatDependencies("testdata/gomod.nested1", "v1.10", "v1.10", selector, git.Rule{Ruleset: git.ReleaseRule})
+	require.NoError(t, err)
+
+	want := []Dependency{{
+		Module:  "github.com/example/lib/v2",
+		Ref:     "github.com/example/lib/v2",
+		RefType: git.NoRef,
+		Ruleset: git.Rule{Ruleset: git.ReleaseRule},
+	}, {
+		Module:  "knative.dev/hack/schema",
+		Ref:     "knative.dev/hack/schema@v1.10.1",
+		RefType: git.ReleaseRef,
+		Ruleset: git.Rule{Ruleset: git.ReleaseRule},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("FloatDependencies() diff(-want,+got):\n", diff)
+	}
+
+	got, err = FloatDependencies("testdata/gomod.nested1", "v1.10", "v2.1", selector, git.Rule{Ruleset: git.ReleaseRule})
+	require.NoError(t, err)
+	if want := "github.com/example/lib/v2@v2.1.3"; got[0].Ref != want {
+		t.Errorf("FloatDependencies() ref = %q, want %q", got[0].Ref, want)
+	}
+}
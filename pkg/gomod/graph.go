@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Edge is a direct requirement of one module on another.
+type Edge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Version string `json:"version"`
+}
+
+// Graph is the directed dependency graph between a set of modules.
+type Graph struct {
+	// Modules is the sorted list of every module in the graph, both the ones
+	// that were read from a go.mod file and the ones only seen as dependencies.
+	Modules []string `json:"modules"`
+	// Edges is the list of requirements, sorted by From and then To.
+	Edges []Edge `json:"edges"`
+}
+
+// ModuleGraph reads the given go.mod files and returns the graph of direct
+// dependencies between them. Only dependencies accepted by the selector are
+// included, and each edge records the version required by the go.mod file.
+func ModuleGraph(gomod []string, selector Matcher) (*Graph, error) {
+	if len(gomod) == 0 {
+		return nil, errors.New("no go module files provided")
+	}
+
+	modules := sets.NewString()
+	edges := make(map[string]Edge)
+	for _, gm := range gomod {
+		file, err := parseModFile(gm)
+		if err != nil {
+			return nil, err
+		}
+		from := file.Module.Mod.Path
+		modules.Insert(from)
+		for _, r := range file.Require {
+			// Do not include indirect dependencies.
+			if r.Indirect || !selector(r.Mod.Path) {
+				continue
+			}
+			modules.Insert(r.Mod.Path)
+			edges[from+" "+r.Mod.Path] = Edge{
+				From:    from,
+				To:      r.Mod.Path,
+				Version: r.Mod.Version,
+			}
+		}
+	}
+
+	g := &Graph{
+		Modules: modules.List(),
+		Edges:   make([]Edge, 0, len(edges)),
+	}
+	for _, e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g, nil
+}
+
+// Dependencies returns the sorted list of modules directly required by module.
+func (g *Graph) Dependencies(module string) []string {
+	deps := make([]string, 0)
+	for _, e := range g.Edges {
+		if e.From == module {
+			deps = append(deps, e.To)
+		}
+	}
+	return deps
+}
+
+// WriteDot writes the graph to out in the Graphviz DOT language. Each edge is
+// labeled with the required version.
+func (g *Graph) WriteDot(out io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph modules {\n")
+	for _, m := range g.Modules {
+		fmt.Fprintf(&b, "\t%q;\n", m)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", e.From, e.To, e.Version)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// FindModules walks the given paths and returns every go.mod file found.
+// Paths that are files are returned as is. Hidden, vendor and testdata
+// directories are skipped, matching what the go tool ignores.
+func FindModules(paths []string) ([]string, error) {
+	gomods := make([]string, 0, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			gomods = append(gomods, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != p && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Name() == "go.mod" {
+				gomods = append(gomods, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return gomods, nil
+}
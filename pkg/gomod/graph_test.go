@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindModules(t *testing.T) {
+	tests := map[string]struct {
+		paths   []string
+		want    []string
+		wantErr bool
+	}{
+		"org checkout": {
+			paths: []string{"testdata/org"},
+			want: []string{
+				"testdata/org/eventing/go.mod",
+				"testdata/org/hack/go.mod",
+				"testdata/org/networking/go.mod",
+				"testdata/org/pkg/go.mod",
+				"testdata/org/serving/go.mod",
+			},
+		},
+		"files and dirs": {
+			paths: []string{"testdata/gomod.example1", "testdata/org/pkg"},
+			want: []string{
+				"testdata/gomod.example1",
+				"testdata/org/pkg/go.mod",
+			},
+		},
+		"missing path": {
+			paths:   []string{"does-not-exist"},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := FindModules(tt.paths)
+			if (tt.wantErr && err == nil) || (!tt.wantErr && err != nil) {
+				t.Errorf("unexpected error state, want error == %t, got %v", tt.wantErr, err)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			for i := range got {
+				got[i] = filepath.ToSlash(got[i])
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("FindModules() diff(-want,+got):\n", diff)
+			}
+		})
+	}
+}
+
+func TestModuleGraph(t *testing.T) {
+	tests := map[string]struct {
+		files   []string
+		domain  string
+		want    *Graph
+		wantErr bool
+	}{
+		"org, knative.dev": {
+			files: []string{
+				"testdata/org/pkg/go.mod",
+				"testdata/org/serving/go.mod",
+				"testdata/org/networking/go.mod",
+			},
+			domain: "knative.dev",
+			want: &Graph{
+				Modules: []string{
+					"knative.dev/caching",
+					"knative.dev/hack",
+					"knative.dev/networking",
+					"knative.dev/pkg",
+					"knative.dev/serving",
+				},
+				Edges: []Edge{
+					{From: "knative.dev/networking", To: "knative.dev/hack", Version: "v0.0.0-20230417170854-f591fea109b3"},
+					{From: "knative.dev/networking", To: "knative.dev/pkg", Version: "v0.0.0-20230418073056-dfad48eaa5d0"},
+					{From: "knative.dev/pkg", To: "knative.dev/hack", Version: "v0.0.0-20230417170854-f591fea109b3"},
+					{From: "knative.dev/serving", To: "knative.dev/caching", Version: "v0.0.0-20230418014357-6ecc60d5d32f"},
+					{From: "knative.dev/serving", To: "knative.dev/hack", Version: "v0.0.0-20230417170854-f591fea109b3"},
+					{From: "knative.dev/serving", To: "knative.dev/networking", Version: "v0.0.0-20230419144338-4b3ad4ba8b2c"},
+					{From: "knative.dev/serving", To: "knative.dev/pkg", Version: "v0.0.0-20230418073056-dfad48eaa5d0"},
+				},
+			},
+		},
+		"leaf module": {
+			files:  []string{"testdata/org/hack/go.mod"},
+			domain: "knative.dev",
+			want: &Graph{
+				Modules: []string{"knative.dev/hack"},
+				Edges:   []Edge{},
+			},
+		},
+		"bad example": {
+			files:   []string{"testdata/org/pkg/go.mod", "testdata/bad.example"},
+			domain:  "knative.dev",
+			wantErr: true,
+		},
+		"no file": {
+			domain:  "knative.dev",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector, err := DomainSelector(tt.domain)
+			if err != nil {
+				t.Fatal("DomainSelector() =", err)
+			}
+			got, err := ModuleGraph(tt.files, selector)
+			if (tt.wantErr && err == nil) || (!tt.wantErr && err != nil) {
+				t.Errorf("unexpected error state, want error == %t, got %v", tt.wantErr, err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("ModuleGraph() diff(-want,+got):\n", diff)
+			}
+		})
+	}
+}
+
+func TestGraphDependencies(t *testing.T) {
+	g := &Graph{
+		Modules: []string{"a", "b", "c"},
+		Edges: []Edge{
+			{From: "a", To: "b"},
+			{From: "a", To: "c"},
+			{From: "b", To: "c"},
+		},
+	}
+	if diff := cmp.Diff([]string{"b", "c"}, g.Dependencies("a")); diff != "" {
+		t.Error("Dependencies(a) diff(-want,+got):\n", diff)
+	}
+	if diff := cmp.Diff([]string{}, g.Dependencies("c")); diff != "" {
+		t.Error("Dependencies(c) diff(-want,+got):\n", diff)
+	}
+}
+
+func TestGraphWriteDot(t *testing.T) {
+	g := &Graph{
+		Modules: []string{"knative.dev/hack", "knative.dev/pkg"},
+		Edges: []Edge{
+			{From: "knative.dev/pkg", To: "knative.dev/hack", Version: "v0.1.0"},
+		},
+	}
+	want := `digraph modules {
+	"knative.dev/hack";
+	"knative.dev/pkg";
+	"knative.dev/pkg" -> "knative.dev/hack" [label="v0.1.0"];
+}
+`
+	var got strings.Builder
+	if err := g.WriteDot(&got); err != nil {
+		t.Fatal("WriteDot() =", err)
+	}
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Error("WriteDot() diff(-want,+got):\n", diff)
+	}
+}
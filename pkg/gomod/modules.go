@@ -53,12 +53,7 @@ func Modules(gomod []string, selector Matcher) (map[string][]string, []string, e
 // Module returns the name and a list of direct dependencies for a given module.
 // TODO: support url and gopath at some point for the gomod string.
 func Module(gomod string, selector Matcher) (string, []string, error) {
-	b, err := ioutil.ReadFile(gomod)
-	if err != nil {
-		return "", nil, err
-	}
-
-	file, err := modfile.Parse(gomod, b /*VersionFixer func*/, nil)
+	file, err := parseModFile(gomod)
 	if err != nil {
 		return "", nil, err
 	}
@@ -77,3 +72,12 @@ func Module(gomod string, selector Matcher) (string, []string, error) {
 
 	return file.Module.Mod.Path, packages.List(), nil
 }
+
+// parseModFile reads and parses the given go.mod file.
+func parseModFile(gomod string) (*modfile.File, error) {
+	b, err := ioutil.ReadFile(gomod)
+	if err != nil {
+		return nil, err
+	}
+	return modfile.Parse(gomod, b /*VersionFixer func*/, nil)
+}
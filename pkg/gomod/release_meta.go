@@ -28,10 +28,10 @@ import (
 
 // ReleaseMeta holds metadata important to module release status.
 type ReleaseMeta struct {
-	Module              string
-	ReleaseBranchExists bool
-	ReleaseBranch       string
-	Release             string
+	Module              string `json:"module"`
+	ReleaseBranchExists bool   `json:"releaseBranchExists"`
+	ReleaseBranch       string `json:"releaseBranch"`
+	Release             string `json:"release"`
 }
 
 // ReleaseStatus collects metadata about release branch status and next released
@@ -52,6 +52,11 @@ func ReleaseStatus(gomod, release, moduleRelease string, out io.Writer) (*Releas
 		return nil, err
 	}
 
+	return releaseStatus(module, r, mr, out)
+}
+
+// releaseStatus collects the ReleaseMeta for a module by name.
+func releaseStatus(module string, r, mr semver.Version, out io.Writer) (*ReleaseMeta, error) {
 	if out != nil {
 		_, _ = fmt.Fprintln(out, module)
 	}
@@ -87,9 +92,15 @@ func ReleaseStatus(gomod, release, moduleRelease string, out io.Writer) (*Releas
 		rv, _ := semver.ParseTolerant(r) // has to parse, r is from BestRefFor
 		rv.Patch++
 
-		next.Release = git.ReleaseVersion(rv)
+		next.Release = repo.ReleaseTag(rv)
 	} else {
-		next.Release = git.ReleaseVersion(mr)
+		// The tag of a module with a major version suffix has to be of
+		// that major.
+		if repo.Major >= 2 && !repo.AcceptsMajor(mr.Major) {
+			return nil, fmt.Errorf("unable to release %s as %s, the major version does not match the module path",
+				module, git.ReleaseVersion(mr))
+		}
+		next.Release = repo.ReleaseTag(mr)
 	}
 
 	if out != nil {
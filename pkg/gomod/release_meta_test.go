@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -79,3 +80,37 @@ func TestReleaseStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseStatus_ModulePath(t *testing.T) {
+	useSnapshot(t)
+
+	got, err := ReleaseStatus("./testdata/gomod.next2", "v1.10", "v1.10", nil)
+	if err != nil {
+		t.Fatal("ReleaseStatus() =", err)
+	}
+	want := &ReleaseMeta{
+		Module:              "knative.dev/hack/schema",
+		ReleaseBranchExists: true,
+		ReleaseBranch:       "release-1.10",
+		Release:             "schema/v1.10.2",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("ReleaseStatus() diff(-want,+got):\n", diff)
+	}
+}
+
+func TestReleaseStatus_MajorVersion(t *testing.T) {
+	useSnapshot(t)
+
+	got, err := releaseStatus("github.com/example/lib/v2", semver.MustParse("2.2.0"), semver.MustParse("2.2.0"), nil)
+	if err != nil {
+		t.Fatal("releaseStatus() =", err)
+	}
+	if got.Release != "v2.2.0" {
+		t.Errorf("releaseStatus() Release = %q, want v2.2.0", got.Release)
+	}
+
+	if got, err := releaseStatus("github.com/example/lib/v2", semver.MustParse("1.12.0"), semver.MustParse("1.12.0"), nil); err == nil {
+		t.Errorf("releaseStatus() = %+v, want an error for a v1 release of a /v2 module", got)
+	}
+}
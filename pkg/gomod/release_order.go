@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// ReleaseOrder groups the modules of the graph into waves. Every module in a
+// wave only depends on modules from earlier waves, so the modules of a wave
+// can be released in parallel once the previous waves are released. Modules
+// in each wave are sorted by name. A *CycleError is returned if the graph has
+// a dependency cycle.
+func ReleaseOrder(g *Graph) ([][]string, error) {
+	// pending counts the not yet released dependencies of each module.
+	pending := make(map[string]int, len(g.Modules))
+	dependents := make(map[string][]string, len(g.Modules))
+	for _, m := range g.Modules {
+		pending[m] = 0
+	}
+	for _, e := range g.Edges {
+		pending[e.From]++
+		dependents[e.To] = append(dependents[e.To], e.From)
+	}
+
+	waves := make([][]string, 0)
+	wave := make([]string, 0)
+	for _, m := range g.Modules {
+		if pending[m] == 0 {
+			wave = append(wave, m)
+		}
+	}
+	released := 0
+	for len(wave) > 0 {
+		waves = append(waves, wave)
+		released += len(wave)
+		next := make([]string, 0)
+		for _, m := range wave {
+			for _, d := range dependents[m] {
+				pending[d]--
+				if pending[d] == 0 {
+					next = append(next, d)
+				}
+			}
+		}
+		sort.Strings(next)
+		wave = next
+	}
+
+	if released != len(pending) {
+		return nil, &CycleError{Cycle: findCycle(g, pending)}
+	}
+	return waves, nil
+}
+
+// findCycle returns one dependency cycle between the modules that could not
+// be released. The first module of the cycle is repeated at the end.
+func findCycle(g *Graph, pending map[string]int) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(pending))
+	stack := make([]string, 0)
+
+	var visit func(string) []string
+	visit = func(m string) []string {
+		state[m] = visiting
+		stack = append(stack, m)
+		for _, d := range g.Dependencies(m) {
+			switch state[d] {
+			case visiting:
+				for i, s := range stack {
+					if s == d {
+						return append(append([]string{}, stack[i:]...), d)
+					}
+				}
+			case unvisited:
+				if cycle := visit(d); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[m] = visited
+		return nil
+	}
+
+	for _, m := range g.Modules {
+		if pending[m] > 0 && state[m] == unvisited {
+			if cycle := visit(m); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// CycleErr is a CycleError instance. For use with with error.Is.
+var CycleErr = &CycleError{}
+
+// CycleError holds a dependency cycle that prevents a release order.
+type CycleError struct {
+	Cycle []string
+}
+
+var _ error = (*CycleError)(nil)
+
+// Is implements error.Is(target)
+func (e *CycleError) Is(target error) bool {
+	_, is := target.(*CycleError)
+	return is
+}
+
+// Error implements error.Error()
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle found [%s]", strings.Join(e.Cycle, " -> "))
+}
+
+// ReleasePlan computes the release order for the modules of the given go.mod
+// files and collects the release status of each module, see ReleaseStatus.
+// Dependencies that were not given as a go.mod file are part of the plan as
+// well, since they have to be released before their dependents.
+func ReleasePlan(gomod []string, release, moduleRelease string, selector Matcher, out io.Writer) ([][]*ReleaseMeta, error) {
+	r, err := semver.ParseTolerant(release)
+	if err != nil {
+		return nil, err
+	}
+
+	mr, err := semver.ParseTolerant(moduleRelease)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := ModuleGraph(gomod, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := ReleaseOrder(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([][]*ReleaseMeta, 0, len(order))
+	for _, modules := range order {
+		wave := make([]*ReleaseMeta, 0, len(modules))
+		for _, module := range modules {
+			meta, err := releaseStatus(module, r, mr, out)
+			if err != nil {
+				return nil, err
+			}
+			wave = append(wave, meta)
+		}
+		plan = append(plan, wave)
+	}
+	return plan, nil
+}
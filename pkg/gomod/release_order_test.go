@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReleaseOrder(t *testing.T) {
+	tests := map[string]struct {
+		graph     *Graph
+		want      [][]string
+		wantCycle []string
+	}{
+		"chain": {
+			graph: &Graph{
+				Modules: []string{"a", "b", "c"},
+				Edges: []Edge{
+					{From: "a", To: "b"},
+					{From: "b", To: "c"},
+				},
+			},
+			want: [][]string{{"c"}, {"b"}, {"a"}},
+		},
+		"diamond": {
+			graph: &Graph{
+				Modules: []string{"a", "b", "c", "d"},
+				Edges: []Edge{
+					{From: "a", To: "b"},
+					{From: "a", To: "c"},
+					{From: "b", To: "d"},
+					{From: "c", To: "d"},
+				},
+			},
+			want: [][]string{{"d"}, {"b", "c"}, {"a"}},
+		},
+		"no edges": {
+			graph: &Graph{
+				Modules: []string{"a", "b"},
+				Edges:   []Edge{},
+			},
+			want: [][]string{{"a", "b"}},
+		},
+		"cycle": {
+			graph: &Graph{
+				Modules: []string{"a", "b", "c", "d"},
+				Edges: []Edge{
+					{From: "a", To: "b"},
+					{From: "b", To: "c"},
+					{From: "c", To: "b"},
+					{From: "c", To: "d"},
+				},
+			},
+			wantCycle: []string{"b", "c", "b"},
+		},
+		"self cycle": {
+			graph: &Graph{
+				Modules: []string{"a"},
+				Edges:   []Edge{{From: "a", To: "a"}},
+			},
+			wantCycle: []string{"a", "a"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ReleaseOrder(tt.graph)
+			if tt.wantCycle != nil {
+				var cerr *CycleError
+				if !errors.As(err, &cerr) {
+					t.Fatalf("ReleaseOrder() = %v, want a CycleError", err)
+				}
+				if !errors.Is(err, CycleErr) {
+					t.Error("errors.Is(err, CycleErr) = false, want true")
+				}
+				if diff := cmp.Diff(tt.wantCycle, cerr.Cycle); diff != "" {
+					t.Error("ReleaseOrder() cycle diff(-want,+got):\n", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("ReleaseOrder() =", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("ReleaseOrder() diff(-want,+got):\n", diff)
+			}
+		})
+	}
+}
+
+func TestReleaseOrderOrg(t *testing.T) {
+	gomods, err := FindModules([]string{"testdata/org"})
+	if err != nil {
+		t.Fatal("FindModules() =", err)
+	}
+	selector, err := DomainSelector("knative.dev")
+	if err != nil {
+		t.Fatal("DomainSelector() =", err)
+	}
+	graph, err := ModuleGraph(gomods, selector)
+	if err != nil {
+		t.Fatal("ModuleGraph() =", err)
+	}
+	got, err := ReleaseOrder(graph)
+	if err != nil {
+		t.Fatal("ReleaseOrder() =", err)
+	}
+	want := [][]string{
+		{"knative.dev/caching", "knative.dev/hack", "knative.dev/reconciler-test"},
+		{"knative.dev/pkg"},
+		{"knative.dev/eventing", "knative.dev/networking"},
+		{"knative.dev/serving"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("ReleaseOrder() diff(-want,+got):\n", diff)
+	}
+}
@@ -55,8 +55,25 @@ func DefaultSelector(domain string) (Matcher, error) {
 	}
 
 	inDomainButNotCurrentWorkspace := func(modname string) bool {
-		return strings.HasPrefix(modname, domain) && !currentModules(modname)
+		return inDomain(modname, domain) && !currentModules(modname)
 	}
 
 	return inDomainButNotCurrentWorkspace, nil
 }
+
+// DomainSelector returns a selector that includes every module with the given
+// domain, including the current project modules.
+func DomainSelector(domain string) (Matcher, error) {
+	domain = strings.TrimSpace(domain)
+	if len(domain) == 0 {
+		return func(string) bool { return true }, ErrNoDomain
+	}
+	return func(modname string) bool {
+		return inDomain(modname, domain)
+	}, nil
+}
+
+// inDomain reports if the module is the domain, or a path under it.
+func inDomain(modname, domain string) bool {
+	return modname == domain || strings.HasPrefix(modname, domain+"/")
+}
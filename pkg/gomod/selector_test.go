@@ -19,6 +19,7 @@ func TestDefaultSelector(t *testing.T) {
 		"github.com/blang/semver/v4",
 		"github.com/google/go-cmp",
 		"go.uber.org/atomic",
+		"knative.dev.evil/serving",
 	}
 	selected := make([]string, 0, len(mods))
 	for _, mod := range mods {
@@ -32,3 +33,18 @@ func TestDefaultSelector(t *testing.T) {
 		"knative.dev/pkg",
 	}, selected)
 }
+
+func TestDomainSelector(t *testing.T) {
+	sel, err := gomod.DomainSelector("knative.dev")
+	require.NoError(t, err)
+	for mod, want := range map[string]bool{
+		"knative.dev":              true,
+		"knative.dev/serving":      true,
+		"knative.dev/serving/v2":   true,
+		"knative.dev.evil/serving": false,
+		"knative.devx":             false,
+		"github.com/knative.dev":   false,
+	} {
+		assert.Equal(t, want, sel(mod), mod)
+	}
+}
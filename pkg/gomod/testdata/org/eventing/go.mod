@@ -0,0 +1,10 @@
+module knative.dev/eventing
+
+go 1.18
+
+require (
+	github.com/cloudevents/sdk-go/v2 v2.13.0
+	knative.dev/hack v0.0.0-20230417170854-f591fea109b3
+	knative.dev/pkg v0.0.0-20230418073056-dfad48eaa5d0
+	knative.dev/reconciler-test v0.0.0-20230418073056-a2ab5d5fe3e1
+)
@@ -0,0 +1,3 @@
+module knative.dev/hack
+
+go 1.18
@@ -0,0 +1,9 @@
+module knative.dev/networking
+
+go 1.18
+
+require (
+	k8s.io/api v0.25.4
+	knative.dev/hack v0.0.0-20230417170854-f591fea109b3
+	knative.dev/pkg v0.0.0-20230418073056-dfad48eaa5d0
+)
@@ -0,0 +1,8 @@
+module knative.dev/pkg
+
+go 1.18
+
+require (
+	github.com/google/go-cmp v0.5.9
+	knative.dev/hack v0.0.0-20230417170854-f591fea109b3
+)
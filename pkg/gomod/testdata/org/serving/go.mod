@@ -0,0 +1,12 @@
+module knative.dev/serving
+
+go 1.18
+
+require (
+	k8s.io/api v0.25.4
+	knative.dev/caching v0.0.0-20230418014357-6ecc60d5d32f
+	knative.dev/hack v0.0.0-20230417170854-f591fea109b3
+	knative.dev/networking v0.0.0-20230419144338-4b3ad4ba8b2c
+	knative.dev/pkg v0.0.0-20230418073056-dfad48eaa5d0
+	knative.dev/test-infra v0.0.0-20230405083057-6b8a7dcc3fe8 // indirect
+)
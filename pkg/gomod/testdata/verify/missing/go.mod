@@ -0,0 +1,12 @@
+module knative.dev/test-verify
+
+go 1.18
+
+require (
+	github.com/google/go-cmp v0.5.9
+	knative.dev/hack v0.0.0-20230113013652-c7cfcb062de9
+	knative.dev/pkg v0.0.0-20230117174043-bbf4a7cbc9b4
+	knative.dev/networking v0.0.0-20230117140541-ca3a1b1aa4ae // indirect
+)
+
+replace knative.dev/pkg => knative.dev/pkg v0.0.0-20230118163327-9f5f5b82f97e
@@ -0,0 +1,18 @@
+module knative.dev/test-verify
+
+go 1.18
+
+require (
+	knative.dev/hack v0.0.0-20230113013652-c7cfcb062de9
+	knative.dev/pkg v0.0.0-20230117174043-bbf4a7cbc9b4
+)
+
+// Only the required version of knative.dev/hack is replaced
+replace knative.dev/hack v0.0.0-20230113013652-c7cfcb062de9 => knative.dev/hack v0.0.0-20230120000000-0a1b2c3d4e5f
+
+replace knative.dev/hack => ../hack
+
+// No replace of knative.dev/pkg matches its required version
+replace knative.dev/pkg v0.0.0-20230101000000-0a1b2c3d4e5f => ../pkg
+
+replace knative.dev/pkg v0.0.0-20230102000000-0a1b2c3d4e5f => knative.dev/pkg v0.0.0-20230118163327-9f5f5b82f97e
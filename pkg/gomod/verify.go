@@ -0,0 +1,265 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	goSumFile      = "go.sum"
+	vendorModsFile = "vendor/modules.txt"
+)
+
+// Mismatch is a selected module whose go.sum or vendor/modules.txt entry does
+// not match the version required by go.mod.
+type Mismatch struct {
+	Module string `json:"module"`
+	// Version is the version required by go.mod, for go.sum the version after
+	// replacements, for vendor/modules.txt including the replacement.
+	Version string `json:"version"`
+	// File is the file that does not match, go.sum or vendor/modules.txt.
+	File string `json:"file"`
+	// Found lists the versions of the module found in File instead, empty if
+	// the module is missing from File.
+	Found []string `json:"found,omitempty"`
+}
+
+// String returns the mismatch in human readable form.
+func (m Mismatch) String() string {
+	if len(m.Found) == 0 {
+		return fmt.Sprintf("%s@%s missing from %s", m.Module, m.Version, m.File)
+	}
+	return fmt.Sprintf("%s@%s does not match %s [%s]", m.Module, m.Version, m.File, strings.Join(m.Found, ", "))
+}
+
+// Verify cross-checks the go.sum and vendor/modules.txt files next to a go.mod
+// file for each selected dependency, and returns a *VerifyError if any of them
+// does not match the version required by go.mod. vendor/modules.txt is only
+// checked if the module is vendored.
+func Verify(gomod string, selector Matcher, out io.Writer) error {
+	module, mismatches, err := VerifyResults(gomod, selector)
+	if err != nil {
+		return err
+	}
+
+	if out != nil {
+		_, _ = fmt.Fprintln(out, module)
+		for _, m := range mismatches {
+			_, _ = fmt.Fprintln(out, "✘ ", m.String())
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &VerifyError{
+			Module:     module,
+			Mismatches: mismatches,
+		}
+	}
+	return nil
+}
+
+// VerifyResults is like Verify, but returns the name of the module and the
+// mismatches instead of an error.
+func VerifyResults(gomod string, selector Matcher) (string, []Mismatch, error) {
+	file, err := parseModFile(gomod)
+	if err != nil {
+		return "", nil, err
+	}
+	dir := filepath.Dir(gomod)
+
+	sums, err := readGoSum(filepath.Join(dir, goSumFile))
+	if err != nil {
+		return "", nil, err
+	}
+	vendored, err := readVendorModules(filepath.Join(dir, filepath.FromSlash(vendorModsFile)))
+	if err != nil {
+		return "", nil, err
+	}
+
+	// replaced maps a module version to its replacement, a module version or
+	// a directory when the version is empty. Replaces of all versions of a
+	// module have an empty version.
+	replaced := make(map[module.Version][2]string, len(file.Replace))
+	for _, r := range file.Replace {
+		replaced[r.Old] = [2]string{r.New.Path, r.New.Version}
+	}
+	replacement := func(m module.Version) ([2]string, bool) {
+		if rep, found := replaced[m]; found {
+			return rep, true
+		}
+		rep, found := replaced[module.Version{Path: m.Path}]
+		return rep, found
+	}
+
+	mismatches := make([]Mismatch, 0)
+	for _, r := range file.Require {
+		// Do not include indirect dependencies.
+		if r.Indirect || !selector(r.Mod.Path) {
+			continue
+		}
+		path, version := r.Mod.Path, r.Mod.Version
+		rep, isReplaced := replacement(r.Mod)
+		if isReplaced {
+			path, version = rep[0], rep[1]
+		}
+
+		// Modules replaced by a directory have no checksum.
+		if version != "" {
+			if !sums[path].Has(version) || !sums[path].Has(version+"/go.mod") {
+				mismatches = append(mismatches, Mismatch{
+					Module:  path,
+					Version: version,
+					File:    goSumFile,
+					Found:   otherVersions(sums[path], version),
+				})
+			}
+		}
+
+		// vendor/modules.txt lists the required version followed by the
+		// replacement, i.e. "v1.0.0 => example.com/fork v1.0.1".
+		want := r.Mod.Version
+		if isReplaced {
+			want = strings.TrimSpace(fmt.Sprintf("%s => %s %s", want, rep[0], rep[1]))
+		}
+		if vendored != nil && !vendored[r.Mod.Path].Has(want) {
+			mismatches = append(mismatches, Mismatch{
+				Module:  r.Mod.Path,
+				Version: want,
+				File:    vendorModsFile,
+				Found:   otherVersions(vendored[r.Mod.Path], want),
+			})
+		}
+	}
+	return file.Module.Mod.Path, mismatches, nil
+}
+
+// otherVersions returns the sorted versions, without the go.mod entries and
+// the given version.
+func otherVersions(versions sets.String, version string) []string {
+	others := sets.NewString()
+	for v := range versions {
+		v = strings.TrimSuffix(v, "/go.mod")
+		if v != version {
+			others.Insert(v)
+		}
+	}
+	if others.Len() == 0 {
+		return nil
+	}
+	return others.List()
+}
+
+// readGoSum returns the versions of each module in a go.sum file, including
+// the "<version>/go.mod" entries. A missing file has no versions.
+func readGoSum(path string) (map[string]sets.String, error) {
+	sums := make(map[string]sets.String)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sums, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		if sums[fields[0]] == nil {
+			sums[fields[0]] = sets.NewString()
+		}
+		sums[fields[0]].Insert(fields[1])
+	}
+	return sums, scanner.Err()
+}
+
+// readVendorModules returns the versions of each module listed in a
+// vendor/modules.txt file, including the replacement if any, or nil if the
+// module is not vendored.
+func readVendorModules(path string) (map[string]sets.String, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vendored := make(map[string]sets.String)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Module lines look like "# path version [=> replacement]", the
+		// "## explicit" annotations and package lines are skipped.
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fields := strings.Fields(line[len("# "):])
+		if len(fields) < 2 || fields[1] == "=>" {
+			continue
+		}
+		if vendored[fields[0]] == nil {
+			vendored[fields[0]] = sets.NewString()
+		}
+		vendored[fields[0]].Insert(strings.Join(fields[1:], " "))
+	}
+	return vendored, scanner.Err()
+}
+
+// VerifyErr is a VerifyError instance. For use with with error.Is.
+var VerifyErr = &VerifyError{}
+
+// VerifyError holds the result of a failed verification.
+type VerifyError struct {
+	Module     string
+	Mismatches []Mismatch
+}
+
+var _ error = (*VerifyError)(nil)
+
+// Is implements error.Is(target)
+func (e *VerifyError) Is(target error) bool {
+	_, is := target.(*VerifyError)
+	return is
+}
+
+// Error implements error.Error()
+func (e *VerifyError) Error() string {
+	mismatches := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		mismatches = append(mismatches, m.String())
+	}
+	sort.Strings(mismatches)
+	return fmt.Sprintf("%s failed verification of go.sum and vendor [%s]",
+		e.Module,
+		strings.Join(mismatches, ", "))
+}
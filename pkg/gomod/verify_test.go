@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyResults(t *testing.T) {
+	tests := map[string]struct {
+		gomod string
+		want  []Mismatch
+	}{
+		"go.sum and vendor match": {
+			gomod: "./testdata/verify/ok/go.mod",
+			want:  []Mismatch{},
+		},
+		"stale go.sum and vendor": {
+			gomod: "./testdata/verify/stale/go.mod",
+			want: []Mismatch{{
+				Module:  "knative.dev/hack",
+				Version: "v0.0.0-20230113013652-c7cfcb062de9",
+				File:    "go.sum",
+				Found:   []string{"v0.0.0-20230101000000-0a1b2c3d4e5f"},
+			}, {
+				Module:  "knative.dev/hack",
+				Version: "v0.0.0-20230113013652-c7cfcb062de9",
+				File:    "vendor/modules.txt",
+				Found:   []string{"v0.0.0-20230101000000-0a1b2c3d4e5f"},
+			}, {
+				Module:  "knative.dev/pkg",
+				Version: "v0.0.0-20230118163327-9f5f5b82f97e",
+				File:    "go.sum",
+			}},
+		},
+		"versioned replaces": {
+			gomod: "./testdata/verify/versioned/go.mod",
+			want:  []Mismatch{},
+		},
+		"missing go.sum, not vendored": {
+			gomod: "./testdata/verify/missing/go.mod",
+			want: []Mismatch{{
+				Module:  "knative.dev/hack",
+				Version: "v0.0.0-20230113013652-c7cfcb062de9",
+				File:    "go.sum",
+			}, {
+				Module:  "knative.dev/pkg",
+				Version: "v0.0.0-20230118163327-9f5f5b82f97e",
+				File:    "go.sum",
+			}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector, err := DomainSelector("knative.dev")
+			require.NoError(t, err)
+
+			module, got, err := VerifyResults(tt.gomod, selector)
+			require.NoError(t, err)
+			if module != "knative.dev/test-verify" {
+				t.Errorf("VerifyResults() module = %q, want %q", module, "knative.dev/test-verify")
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("VerifyResults() (-want, +got) =", diff)
+			}
+
+			err = Verify(tt.gomod, selector, nil)
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+			} else if !errors.Is(err, VerifyErr) {
+				t.Errorf("Verify() = %v, want a VerifyError", err)
+			}
+		})
+	}
+}
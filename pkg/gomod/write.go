@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+
+	"knative.dev/test-infra/pkg/git"
+)
+
+// Resolver resolves a ref of a module, a tag or a branch, to a go module
+// version that can be written to a go.mod file.
+type Resolver func(module, ref string) (string, error)
+
+// GoListResolver returns a resolver running `go list -m` in dir, the
+// directory of the go.mod file, the same way `go get` does: branches are
+// resolved to pseudo-versions. The module mode is forced, and go.work files
+// are ignored, so that the result doesn't depend on the environment. The go
+// command reaches the network on its own, without the git store of the
+// --cache-dir and --snapshot flags of buoy.
+func GoListResolver(dir string) Resolver {
+	return func(module, ref string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		c := exec.Command("go", "list", "-m", "-json", module+"@"+ref)
+		c.Dir = dir
+		c.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+		c.Stdout = &stdout
+		c.Stderr = &stderr
+		if err := c.Run(); err != nil {
+			return "", fmt.Errorf("unable to resolve %s@%s: %w: %s", module, ref, err, stderr.String())
+		}
+
+		mod := struct{ Version string }{}
+		if err := json.Unmarshal(stdout.Bytes(), &mod); err != nil {
+			return "", fmt.Errorf("unable to resolve %s@%s: %w", module, ref, err)
+		}
+		return mod.Version, nil
+	}
+}
+
+// FileChange is the old and new content of a rewritten file.
+type FileChange struct {
+	Path string
+	Old  []byte
+	New  []byte
+}
+
+// Changed returns true if the new content differs from the old one.
+func (c *FileChange) Changed() bool {
+	return !bytes.Equal(c.Old, c.New)
+}
+
+// Diff writes the change as a unified diff to out.
+func (c *FileChange) Diff(out io.Writer) error {
+	if !c.Changed() {
+		return nil
+	}
+	return difflib.WriteUnifiedDiff(out, difflib.UnifiedDiff{
+		A:        splitLines(c.Old),
+		B:        splitLines(c.New),
+		FromFile: "a/" + c.Path,
+		ToFile:   "b/" + c.Path,
+		Context:  3,
+	})
+}
+
+// splitLines splits b into lines, keeping the line endings.
+func splitLines(b []byte) []string {
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Write writes the new content to the file, if it changed.
+func (c *FileChange) Write() error {
+	if !c.Changed() {
+		return nil
+	}
+	info, err := os.Stat(c.Path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.Path, c.New, info.Mode())
+}
+
+// UpdateRefs applies the refs, in the form "module@ref" as returned by Float,
+// to the requirements of the given go.mod file. Comments and replace
+// directives are preserved. If gowork is not empty, the replace directives of
+// that go.work file that pin one of the modules to a version are updated as
+// well. Refs that are not semver versions are resolved with resolve. The
+// files are not written, see FileChange.Write.
+func UpdateRefs(gomod, gowork string, refs []string, resolve Resolver) ([]*FileChange, error) {
+	versions := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		module, version, refType := git.ParseRef(ref)
+		if refType == git.UndefinedRef {
+			return nil, fmt.Errorf("invalid ref %q, expected module@ref", ref)
+		}
+		if !semver.IsValid(version) {
+			v, err := resolve(module, version)
+			if err != nil {
+				return nil, err
+			}
+			version = v
+		}
+		versions[module] = version
+	}
+
+	changes := make([]*FileChange, 0, 2)
+	change, err := updateModFile(gomod, versions)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, change)
+
+	if gowork != "" {
+		change, err := updateWorkFile(gowork, versions)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func updateModFile(gomod string, versions map[string]string) (*FileChange, error) {
+	b, err := ioutil.ReadFile(gomod)
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.Parse(gomod, b, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range file.Require {
+		if v, found := versions[r.Mod.Path]; found && r.Mod.Version != v {
+			if err := file.AddRequire(r.Mod.Path, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	file.Cleanup()
+
+	formatted, err := file.Format()
+	if err != nil {
+		return nil, err
+	}
+	return &FileChange{Path: gomod, Old: b, New: formatted}, nil
+}
+
+func updateWorkFile(gowork string, versions map[string]string) (*FileChange, error) {
+	b, err := ioutil.ReadFile(gowork)
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.ParseWork(gowork, b, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range file.Replace {
+		// Only replacements of a module by a version of itself are pinned
+		// versions, leave forks and local directories alone.
+		if r.New.Path != r.Old.Path || r.New.Version == "" {
+			continue
+		}
+		if v, found := versions[r.Old.Path]; found && r.New.Version != v {
+			if err := file.AddReplace(r.Old.Path, r.Old.Version, r.New.Path, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	file.Cleanup()
+
+	return &FileChange{Path: gowork, Old: b, New: modfile.Format(file.Syntax)}, nil
+}
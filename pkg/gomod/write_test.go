@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomod
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func fakeResolver(module, ref string) (string, error) {
+	if ref == "release-1.10" {
+		return "v0.0.0-20230502134655-0b2e6a8b1ad1", nil
+	}
+	return "", errors.New("unknown ref " + module + "@" + ref)
+}
+
+func TestUpdateRefs(t *testing.T) {
+	changes, err := UpdateRefs("testdata/gomod.write1", "testdata/gowork.write1", []string{
+		"knative.dev/eventing@v0.37.1",
+		"knative.dev/pkg@release-1.10",
+	}, fakeResolver)
+	if err != nil {
+		t.Fatal("UpdateRefs() =", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("UpdateRefs() returned %d changes, want 2", len(changes))
+	}
+
+	wantMod := `module knative.dev/test-demo1
+
+go 1.18
+
+require (
+	github.com/google/go-cmp v0.5.9
+	// Keep eventing on the release branch.
+	knative.dev/eventing v0.37.1
+	knative.dev/pkg v0.0.0-20230502134655-0b2e6a8b1ad1 // floated by buoy
+	knative.dev/serving v0.37.0
+)
+
+replace knative.dev/serving => ../serving
+`
+	if diff := cmp.Diff(wantMod, string(changes[0].New)); diff != "" {
+		t.Error("go.mod diff(-want,+got):\n", diff)
+	}
+
+	wantWork := `go 1.18
+
+use .
+
+replace (
+	knative.dev/pkg => knative.dev/pkg v0.0.0-20230502134655-0b2e6a8b1ad1
+	knative.dev/serving => ../serving
+)
+`
+	if diff := cmp.Diff(wantWork, string(changes[1].New)); diff != "" {
+		t.Error("go.work diff(-want,+got):\n", diff)
+	}
+
+	var diff strings.Builder
+	if err := changes[1].Diff(&diff); err != nil {
+		t.Fatal("Diff() =", err)
+	}
+	wantDiff := `--- a/testdata/gowork.write1
++++ b/testdata/gowork.write1
+@@ -3,6 +3,6 @@
+ use .
+ 
+ replace (
+-	knative.dev/pkg => knative.dev/pkg v0.0.0-20230418073056-dfad48eaa5d0
++	knative.dev/pkg => knative.dev/pkg v0.0.0-20230502134655-0b2e6a8b1ad1
+ 	knative.dev/serving => ../serving
+ )
+`
+	if d := cmp.Diff(wantDiff, diff.String()); d != "" {
+		t.Error("Diff() diff(-want,+got):\n", d)
+	}
+}
+
+func TestUpdateRefs_Errors(t *testing.T) {
+	tests := map[string]struct {
+		gomod string
+		refs  []string
+	}{
+		"invalid ref": {
+			gomod: "testdata/gomod.write1",
+			refs:  []string{"knative.dev/pkg"},
+		},
+		"unresolved ref": {
+			gomod: "testdata/gomod.write1",
+			refs:  []string{"knative.dev/pkg@main"},
+		},
+		"bad example": {
+			gomod: "testdata/bad.example",
+			refs:  []string{"knative.dev/pkg@v0.1.0"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := UpdateRefs(tt.gomod, "", tt.refs, fakeResolver); err == nil {
+				t.Error("UpdateRefs() expected an error")
+			}
+		})
+	}
+}
+
+func TestFileChange_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	if err := ioutil.WriteFile(path, []byte("module a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	change := &FileChange{Path: path, Old: []byte("module a\n"), New: []byte("module b\n")}
+	if err := change.Write(); err != nil {
+		t.Fatal("Write() =", err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "module b\n"; got != want {
+		t.Errorf("Write() wrote %q, want %q", got, want)
+	}
+}
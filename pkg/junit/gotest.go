@@ -0,0 +1,253 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gotest.go converts the events of `go test -json` into junit results
+
+package junit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// BuildFailedTestName is the name of the failed test case of a package
+	// that failed to build
+	BuildFailedTestName = "[build failed]"
+	// PackageFailedTestName is the name of the failed test case of a package
+	// that failed outside of its tests, i.e. in TestMain, in a panic, or on
+	// timeout
+	PackageFailedTestName = "[package failed]"
+
+	// maxGoTestLineSize is the max size of a line of the stream, as tests
+	// can log long lines
+	maxGoTestLineSize = 16 * 1024 * 1024
+)
+
+// GoTestEvent is an event of `go test -json`, see `go doc test2json`
+type GoTestEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64 // seconds
+	Output  string
+	// ImportPath and FailedBuild are set for build events, by Go 1.24 and
+	// later
+	ImportPath  string
+	FailedBuild string
+}
+
+// goTest accumulates the events of a test
+type goTest struct {
+	name    string
+	action  string // pass, fail, skip, or empty if the test didn't end
+	elapsed float64
+	output  strings.Builder
+}
+
+// goPackage accumulates the events of a package
+type goPackage struct {
+	name        string
+	action      string
+	elapsed     float64
+	failedBuild string
+	output      strings.Builder
+	tests       []*goTest
+	testIndexes map[string]int
+}
+
+func (p *goPackage) getTest(name string) *goTest {
+	if i, ok := p.testIndexes[name]; ok {
+		return p.tests[i]
+	}
+	t := &goTest{name: name}
+	p.testIndexes[name] = len(p.tests)
+	p.tests = append(p.tests, t)
+	return t
+}
+
+// isFrameOutput reports if an output line is added by the testing package
+// around the output of a test, which is redundant in junit
+func isFrameOutput(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range []string{"=== RUN", "=== PAUSE", "=== CONT", "=== NAME", "--- PASS:", "--- FAIL:", "--- SKIP:"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromGoTestJSON converts the events of `go test -json` read from r into
+// junit results, with a suite per package. Test cases are named like the
+// tests, subtests included (i.e. "TestFoo/bar"), so that parents of subtests
+// are test cases too. Their output is kept as system-out, and as the failure
+// message of failed tests. Packages that failed outside of their tests get a
+// failed BuildFailedTestName or PackageFailedTestName test case, with the
+// build errors or the package output as failure message. Lines that are not
+// JSON, i.e. build errors of older Go versions when stderr is redirected to
+// the stream, are used as build errors of the package of the last
+// "# <package>" header read before them. Lines read before any header are
+// used as build errors of the next package that failed to build without
+// errors of its own.
+func FromGoTestJSON(r io.Reader) (*TestSuites, error) {
+	var (
+		packages      []*goPackage
+		packageIdx    = make(map[string]int)
+		buildOutputs  = make(map[string]*strings.Builder) // key is the import path of the build
+		headerOutputs = make(map[string]*strings.Builder) // key is the package of the "# <package>" header
+		headerPackage string
+		pendingOutput strings.Builder
+	)
+	getPackage := func(name string) *goPackage {
+		if i, ok := packageIdx[name]; ok {
+			return packages[i]
+		}
+		p := &goPackage{name: name, testIndexes: make(map[string]int)}
+		packageIdx[name] = len(packages)
+		packages = append(packages, p)
+		return p
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxGoTestLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var e GoTestEvent
+		if len(line) == 0 || line[0] != '{' || json.Unmarshal(line, &e) != nil {
+			if name, ok := parseBuildHeader(string(line)); ok {
+				headerPackage = name
+				if _, ok := headerOutputs[name]; !ok {
+					headerOutputs[name] = &strings.Builder{}
+				}
+			}
+			output := &pendingOutput
+			if headerPackage != "" {
+				output = headerOutputs[headerPackage]
+			}
+			output.Write(line)
+			output.WriteByte('\n')
+			continue
+		}
+		switch e.Action {
+		case "build-output":
+			if _, ok := buildOutputs[e.ImportPath]; !ok {
+				buildOutputs[e.ImportPath] = &strings.Builder{}
+			}
+			buildOutputs[e.ImportPath].WriteString(e.Output)
+			continue
+		case "build-fail", "start", "pause", "cont", "bench":
+			continue
+		}
+		if e.Package == "" {
+			continue
+		}
+		p := getPackage(e.Package)
+		if e.Test == "" {
+			switch e.Action {
+			case "output":
+				p.output.WriteString(e.Output)
+			case "pass", "fail", "skip":
+				p.action, p.elapsed, p.failedBuild = e.Action, e.Elapsed, e.FailedBuild
+			}
+			continue
+		}
+		t := p.getTest(e.Test)
+		switch e.Action {
+		case "output":
+			if !isFrameOutput(e.Output) {
+				t.output.WriteString(e.Output)
+			}
+		case "pass", "fail", "skip":
+			t.action, t.elapsed = e.Action, e.Elapsed
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading go test events: %v", err)
+	}
+
+	testSuites := &TestSuites{}
+	for _, p := range packages {
+		suite := TestSuite{Name: p.name, Time: formatSeconds(p.elapsed)}
+		testFailed := false
+		for _, t := range p.tests {
+			output := t.output.String()
+			tc := TestCase{Name: t.name, ClassName: p.name, Time: formatSeconds(t.elapsed)}
+			if output != "" {
+				tc.SystemOut = &output
+			}
+			switch t.action {
+			case "pass":
+			case "skip":
+				tc.Skipped = NewResult(output)
+			default: // failed, or didn't end as the package panicked or timed out
+				testFailed = true
+				tc.Failure = NewResult(output)
+			}
+			suite.AddTestCase(tc)
+		}
+		if p.action == "fail" && !testFailed {
+			output := p.output.String()
+			tc := TestCase{Name: PackageFailedTestName, ClassName: p.name}
+			if p.failedBuild != "" || strings.Contains(output, BuildFailedTestName) {
+				tc.Name = BuildFailedTestName
+				if bo, ok := buildOutputs[p.failedBuild]; ok {
+					output = bo.String()
+				} else if ho, ok := headerOutputs[p.name]; ok {
+					output = ho.String()
+				} else if pendingOutput.Len() > 0 {
+					output = pendingOutput.String()
+					pendingOutput.Reset()
+				}
+			}
+			tc.Failure = NewResult(output)
+			suite.AddTestCase(tc)
+		}
+		if len(suite.TestCases) > 0 {
+			testSuites.Suites = append(testSuites.Suites, suite)
+		}
+	}
+	return testSuites, nil
+}
+
+// parseBuildHeader returns the package of a "# <package>" header printed by
+// the go command before the build errors of a package. The headers of test
+// builds name the test binary too, i.e. "# <package>_test [<package>.test]",
+// which names the package the errors are reported for.
+func parseBuildHeader(line string) (string, bool) {
+	if !strings.HasPrefix(line, "# ") {
+		return "", false
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "# "))
+	if len(fields) == 0 {
+		return "", false
+	}
+	if len(fields) > 1 && strings.HasPrefix(fields[1], "[") && strings.HasSuffix(fields[1], ".test]") {
+		return strings.TrimSuffix(strings.TrimPrefix(fields[1], "["), ".test]"), true
+	}
+	return fields[0], true
+}
+
+// formatSeconds formats a duration in seconds like the time attributes of
+// junit
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
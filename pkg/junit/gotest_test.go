@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package junit
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFromGoTestJSON(t *testing.T) {
+	f, err := os.Open("testdata/gotest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	testSuites, err := FromGoTestJSON(f)
+	if err != nil {
+		t.Fatalf("FromGoTestJSON() = %v", err)
+	}
+
+	type result struct {
+		status  TestStatusEnum
+		time    string
+		message string // failure or skip message
+		output  string
+	}
+	got := make(map[string]map[string]result)
+	var suites []string
+	for _, suite := range testSuites.Suites {
+		suites = append(suites, suite.Name)
+		got[suite.Name] = make(map[string]result)
+		for _, tc := range suite.TestCases {
+			r := result{status: tc.GetTestStatus(), time: tc.Time}
+			switch {
+			case tc.Failure != nil:
+				r.message = tc.Failure.Data
+			case tc.Skipped != nil:
+				r.message = tc.Skipped.Data
+			}
+			if tc.SystemOut != nil {
+				r.output = *tc.SystemOut
+			}
+			got[suite.Name][tc.Name] = r
+		}
+	}
+
+	if want := []string{"example.com/gt/a", "example.com/gt/b", "example.com/gt/c", "example.com/gt/d", "example.com/gt/e", "example.com/gt/g", "example.com/gt/h"}; !reflect.DeepEqual(suites, want) {
+		t.Errorf("FromGoTestJSON() suites = %v, want %v", suites, want)
+	}
+	want := map[string]map[string]result{
+		"example.com/gt/a": {
+			"TestPass":    {status: Passed, time: "0.250", output: "    a_test.go:3: hello\n"},
+			"TestSub":     {status: Failed, time: "0.000"},
+			"TestSub/ok":  {status: Passed, time: "0.000"},
+			"TestSub/bad": {status: Failed, time: "0.000", message: "    a_test.go:6: boom\n", output: "    a_test.go:6: boom\n"},
+			"TestSkip":    {status: Skipped, time: "0.000", message: "    a_test.go:8: nope\n", output: "    a_test.go:8: nope\n"},
+		},
+		"example.com/gt/b": {
+			BuildFailedTestName: {status: Failed, message: "# example.com/gt/b [example.com/gt/b.test]\nb/b_test.go:3:28: undefined: undefined\n"},
+		},
+		"example.com/gt/c": {
+			BuildFailedTestName: {status: Failed, message: "# example.com/gt/c\nc/c_test.go:3:28: undefined: foo\n"},
+		},
+		"example.com/gt/d": {
+			"TestSlow": {status: Failed, time: "0.000", message: "    d_test.go:5: waiting\n", output: "    d_test.go:5: waiting\n"},
+		},
+		"example.com/gt/e": {
+			PackageFailedTestName: {status: Failed, message: "setup failed: no cluster\nFAIL\texample.com/gt/e\t0.010s\n"},
+		},
+		// Build errors are printed before the results of the packages, and
+		// not in the same order
+		"example.com/gt/g": {
+			BuildFailedTestName: {status: Failed, message: "# example.com/gt/g_test [example.com/gt/g.test]\ng/g_test.go:5:2: undefined: bar\n"},
+		},
+		"example.com/gt/h": {
+			BuildFailedTestName: {status: Failed, message: "# example.com/gt/h\nh/h.go:3:1: syntax error: non-declaration statement outside function body\n"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromGoTestJSON() = %+v, want %+v", got, want)
+	}
+
+	a := testSuites.Suites[0]
+	if a.Tests != 5 || a.Failures != 2 {
+		t.Errorf("FromGoTestJSON() suite has %d tests and %d failures, want 5 and 2", a.Tests, a.Failures)
+	}
+	if d := testSuites.Suites[3]; d.Time != "1.002" {
+		t.Errorf("FromGoTestJSON() suite time = %q, want %q", d.Time, "1.002")
+	}
+}
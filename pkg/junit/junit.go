@@ -14,7 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package junit is DEPRECATED. Please use github.com/jstemmer/go-junit-report as a library.
+// Package junit reads, writes and processes junit results, and creates them
+// from the events of `go test -json`.
 package junit
 
 import (
@@ -34,12 +35,23 @@ const (
 	Skipped TestStatusEnum = "skipped"
 	// Passed means junit test passed
 	Passed TestStatusEnum = "passed"
+
+	// disabledStatus is the status attribute of disabled test cases
+	disabledStatus = "disabled"
 )
 
-// TestSuites holds a <testSuites/> list of TestSuite results
+// TestSuites holds a <testSuites/> list of TestSuite results. Types follow
+// the Jenkins/Ant junit schema, attributes added to it are omitted when
+// empty so that files with only the basic attributes are written unchanged.
 type TestSuites struct {
-	XMLName xml.Name    `xml:"testsuites"`
-	Suites  []TestSuite `xml:"testsuite"`
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr,omitempty"`
+	Time     string      `xml:"time,attr,omitempty"` // Seconds
+	Tests    int         `xml:"tests,attr,omitempty"`
+	Failures int         `xml:"failures,attr,omitempty"`
+	Errors   int         `xml:"errors,attr,omitempty"`
+	Disabled int         `xml:"disabled,attr,omitempty"`
+	Suites   []TestSuite `xml:"testsuite"`
 }
 
 // TestSuite holds <testSuite/> results
@@ -49,20 +61,92 @@ type TestSuite struct {
 	Time       string         `xml:"time,attr"` // Seconds
 	Failures   int            `xml:"failures,attr"`
 	Tests      int            `xml:"tests,attr"`
+	Errors     int            `xml:"errors,attr,omitempty"`
+	Skipped    int            `xml:"skipped,attr,omitempty"`
+	Disabled   int            `xml:"disabled,attr,omitempty"`
+	ID         string         `xml:"id,attr,omitempty"`
+	Package    string         `xml:"package,attr,omitempty"`
+	Hostname   string         `xml:"hostname,attr,omitempty"`
+	Timestamp  string         `xml:"timestamp,attr,omitempty"` // ISO 8601, i.e. 2006-01-02T15:04:05
 	TestCases  []TestCase     `xml:"testcase"`
 	Properties TestProperties `xml:"properties"`
+	SystemOut  *string        `xml:"system-out,omitempty"`
+	SystemErr  *string        `xml:"system-err,omitempty"`
 }
 
-// TestCase holds <testcase/> results
+// TestCase holds <testcase/> results. Failure, Error and Skipped are the
+// <failure/>, <error/> and <skipped/> elements, nil when missing, and the
+// <system-err/> output of the test case is SystemErr, not Error.
 type TestCase struct {
 	Name       string          `xml:"name,attr"`
 	Time       string          `xml:"time,attr"` // Seconds
 	ClassName  string          `xml:"classname,attr"`
-	Failure    *string         `xml:"failure,omitempty"`
-	Output     *string         `xml:"system-out,omitempty"`
-	Error      *string         `xml:"system-err,omitempty"`
-	Skipped    *string         `xml:"skipped,omitempty"`
+	Assertions string          `xml:"assertions,attr,omitempty"`
+	Status     string          `xml:"status,attr,omitempty"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"` // ISO 8601, set by some tools
+	Hostname   string          `xml:"hostname,attr,omitempty"`  // set by some tools
+	Failure    *Result         `xml:"failure,omitempty"`
+	Error      *Result         `xml:"error,omitempty"`
+	SystemOut  *string         `xml:"system-out,omitempty"`
+	SystemErr  *string         `xml:"system-err,omitempty"`
+	Skipped    *Result         `xml:"skipped,omitempty"`
 	Properties *TestProperties `xml:"properties,omitempty"`
+
+	// Output is the <system-out/> output of the test case, set when
+	// unmarshaling and written when SystemOut is nil.
+	//
+	// Deprecated: use SystemOut.
+	Output *string `xml:"-"`
+}
+
+// testCaseXML has the fields of TestCase, without its XML methods
+type testCaseXML TestCase
+
+// UnmarshalXML implements xml.Unmarshaler, setting the deprecated Output
+func (testCase *TestCase) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var tc testCaseXML
+	if err := d.DecodeElement(&tc, &start); err != nil {
+		return err
+	}
+	*testCase = TestCase(tc)
+	testCase.Output = testCase.SystemOut
+	return nil
+}
+
+// MarshalXML implements xml.Marshaler, writing the deprecated Output if
+// SystemOut is nil
+func (testCase TestCase) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	tc := testCaseXML(testCase)
+	if tc.SystemOut == nil {
+		tc.SystemOut = tc.Output
+	}
+	return e.EncodeElement(tc, start)
+}
+
+// Result holds the <failure/>, <error/> or <skipped/> result of a test case.
+// A failure is an assertion that failed, while an error is an unexpected
+// problem, i.e. a panic or a setup that failed.
+type Result struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Data    string `xml:",chardata"`
+}
+
+// NewResult creates a result with data, i.e. a failure message
+func NewResult(data string) *Result {
+	return &Result{Data: data}
+}
+
+// String returns the message and the data of the result
+func (r *Result) String() string {
+	switch {
+	case r.Message == "":
+		return r.Data
+	case r.Data == "":
+		return r.Message
+	default:
+		return r.Message + "\n" + r.Data
+	}
 }
 
 // TestProperties is an array of test properties
@@ -76,18 +160,43 @@ type TestProperty struct {
 	Value string `xml:"value,attr"`
 }
 
-// GetTestStatus returns the test status as a string
+// GetTestStatus returns the test status as a string. Errored tests are
+// failed, see IsErrored to tell them apart, and disabled tests are skipped,
+// see IsDisabled.
 func (testCase *TestCase) GetTestStatus() TestStatusEnum {
 	testStatus := Passed
 	switch {
-	case testCase.Failure != nil:
+	case testCase.Failure != nil, testCase.Error != nil:
 		testStatus = Failed
-	case testCase.Skipped != nil:
+	case testCase.Skipped != nil, testCase.IsDisabled():
 		testStatus = Skipped
 	}
 	return testStatus
 }
 
+// IsErrored reports if the test case has an error and no failure
+func (testCase *TestCase) IsErrored() bool {
+	return testCase.Failure == nil && testCase.Error != nil
+}
+
+// IsDisabled reports if the test case has the disabled status and didn't
+// fail
+func (testCase *TestCase) IsDisabled() bool {
+	return testCase.Status == disabledStatus && testCase.Failure == nil && testCase.Error == nil
+}
+
+// FailureMessage returns the failure, or else the error, of the test case,
+// or an empty string if it didn't fail
+func (testCase *TestCase) FailureMessage() string {
+	switch {
+	case testCase.Failure != nil:
+		return testCase.Failure.String()
+	case testCase.Error != nil:
+		return testCase.Error.String()
+	}
+	return ""
+}
+
 // AddProperty adds property to testcase
 func (testCase *TestCase) AddProperty(name, val string) {
 	if testCase.Properties == nil {
@@ -100,10 +209,23 @@ func (testCase *TestCase) AddProperty(name, val string) {
 // AddTestCase adds a testcase to the testsuite
 func (ts *TestSuite) AddTestCase(tc TestCase) {
 	ts.Tests++
-	if tc.GetTestStatus() == Failed {
+	ts.countTestCase(&tc)
+	ts.TestCases = append(ts.TestCases, tc)
+}
+
+// countTestCase adds the test case to the counts of failures, errors,
+// disabled and skipped tests
+func (ts *TestSuite) countTestCase(tc *TestCase) {
+	switch {
+	case tc.IsErrored():
+		ts.Errors++
+	case tc.GetTestStatus() == Failed:
 		ts.Failures++
+	case tc.IsDisabled():
+		ts.Disabled++
+	case tc.GetTestStatus() == Skipped:
+		ts.Skipped++
 	}
-	ts.TestCases = append(ts.TestCases, tc)
 }
 
 // GetTestSuite gets TestSuite struct by name
@@ -154,9 +276,9 @@ func UnMarshal(buf []byte) (*TestSuites, error) {
 func CreateXMLErrorMsg(testSuite, testName, errMsg, dest string) {
 	suites := TestSuites{}
 	suite := TestSuite{Name: testSuite}
-	var errP *string
+	var errP *Result
 	if errMsg != "" {
-		errP = &errMsg
+		errP = NewResult(errMsg)
 	}
 	suite.AddTestCase(TestCase{
 		Name:    testName,
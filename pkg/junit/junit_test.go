@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"reflect"
 	"testing"
 )
 
@@ -75,19 +76,40 @@ var validSuitesString = `
 </testsuites>
 `
 
+var fullSchemaString = `
+<testsuites name="all" time="3.5" tests="4" failures="1" errors="1" disabled="1">
+	<testsuite name="knative/serving" time="3.5" failures="1" tests="4" errors="1" skipped="1" disabled="1" id="0" package="knative.dev/serving" hostname="runner-1" timestamp="2026-01-02T03:04:05">
+		<testcase name="TestFailure" time="1.5" classname="knative.dev/serving" assertions="2">
+			<failure message="expected 1, got 2" type="AssertionError">serving_test.go:10: expected 1, got 2</failure>
+			<system-out>out</system-out>
+			<system-err>err</system-err>
+		</testcase>
+		<testcase name="TestError" time="2" classname="knative.dev/serving" timestamp="2026-01-02T03:04:06" hostname="runner-1">
+			<error message="panic: nil pointer" type="runtime.Error">goroutine 1 [running]</error>
+		</testcase>
+		<testcase name="TestSkip" time="0" classname="knative.dev/serving">
+			<skipped message="flaky"></skipped>
+		</testcase>
+		<testcase name="TestDisabled" time="0" classname="knative.dev/serving" status="disabled"></testcase>
+		<properties>
+			<property name="go.version" value="go1.18"/>
+		</properties>
+		<system-out>suite out</system-out>
+		<system-err>suite err</system-err>
+	</testsuite>
+</testsuites>
+`
+
 func newTestCase(name string, status TestStatusEnum) *TestCase {
 	testCase := TestCase{
 		Name: name,
 	}
 
-	var tmp string // cast const to string
 	switch {
 	case status == Failed:
-		tmp = string(Failed)
-		testCase.Failure = &tmp
+		testCase.Failure = NewResult(string(Failed))
 	case status == Skipped:
-		tmp = string(Skipped)
-		testCase.Skipped = &tmp
+		testCase.Skipped = NewResult(string(Skipped))
 	}
 
 	return &testCase
@@ -129,6 +151,109 @@ func TestGetTestStatus(t *testing.T) {
 	}
 }
 
+func TestGetTestStatusErrored(t *testing.T) {
+	tc := TestCase{Name: "TestError", Error: &Result{Message: "panic", Data: "goroutine 1"}}
+	if status := tc.GetTestStatus(); status != Failed {
+		t.Errorf("GetTestStatus() = %q, want %q", status, Failed)
+	}
+	if !tc.IsErrored() {
+		t.Error("IsErrored() = false, want true")
+	}
+	if got, want := tc.FailureMessage(), "panic\ngoroutine 1"; got != want {
+		t.Errorf("FailureMessage() = %q, want %q", got, want)
+	}
+	tc.Failure = NewResult("assertion")
+	if tc.IsErrored() {
+		t.Error("IsErrored() = true for a failed test, want false")
+	}
+	if got, want := tc.FailureMessage(), "assertion"; got != want {
+		t.Errorf("FailureMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestGetTestStatusDisabled(t *testing.T) {
+	tc := TestCase{Name: "TestDisabled", Status: "disabled"}
+	if status := tc.GetTestStatus(); status != Skipped {
+		t.Errorf("GetTestStatus() = %q, want %q", status, Skipped)
+	}
+	if !tc.IsDisabled() {
+		t.Error("IsDisabled() = false, want true")
+	}
+	tc.Failure = NewResult("assertion")
+	if status := tc.GetTestStatus(); status != Failed || tc.IsDisabled() {
+		t.Errorf("GetTestStatus() = %q, IsDisabled() = %t, want %q and false for a failed test", status, tc.IsDisabled(), Failed)
+	}
+}
+
+func TestFullSchemaRoundTrip(t *testing.T) {
+	testSuites, err := UnMarshal([]byte(fullSchemaString))
+	if err != nil {
+		t.Fatalf("UnMarshal() = %v", err)
+	}
+	suite := testSuites.Suites[0]
+	if suite.Errors != 1 || suite.Skipped != 1 || suite.Disabled != 1 || suite.Hostname != "runner-1" ||
+		suite.Timestamp != "2026-01-02T03:04:05" || suite.Package != "knative.dev/serving" || *suite.SystemErr != "suite err" {
+		t.Errorf("suite attributes were not parsed: %+v", suite)
+	}
+	failure := suite.TestCases[0].Failure
+	if failure.Message != "expected 1, got 2" || failure.Type != "AssertionError" || failure.Data != "serving_test.go:10: expected 1, got 2" {
+		t.Errorf("Failure = %+v, want message, type and data", failure)
+	}
+	if errored := suite.TestCases[1]; !errored.IsErrored() || errored.Error.Type != "runtime.Error" {
+		t.Errorf("TestCases[1] = %+v, want an error", errored)
+	}
+
+	buf, err := testSuites.ToBytes("", "")
+	if err != nil {
+		t.Fatalf("ToBytes() = %v", err)
+	}
+	got, err := UnMarshal(buf)
+	if err != nil {
+		t.Fatalf("UnMarshal() of written results = %v", err)
+	}
+	if !reflect.DeepEqual(got, testSuites) {
+		t.Errorf("results changed after writing them:\ngot:  %+v\nwant: %+v", got, testSuites)
+	}
+}
+
+func TestDeprecatedOutput(t *testing.T) {
+	testSuites, err := UnMarshal([]byte(`<testsuite name="a"><testcase name="TestA"><system-out>hello</system-out></testcase></testsuite>`))
+	if err != nil {
+		t.Fatalf("UnMarshal() = %v", err)
+	}
+	if tc := testSuites.Suites[0].TestCases[0]; tc.Output == nil || *tc.Output != "hello" {
+		t.Errorf("Output = %v, want %q", tc.Output, "hello")
+	}
+
+	output := "bye"
+	suite := TestSuite{Name: "b"}
+	suite.AddTestCase(TestCase{Name: "TestB", Output: &output})
+	buf, err := (&TestSuites{Suites: []TestSuite{suite}}).ToBytes("", "")
+	if err != nil {
+		t.Fatalf("ToBytes() = %v", err)
+	}
+	got, err := UnMarshal(buf)
+	if err != nil {
+		t.Fatalf("UnMarshal() of written results = %v", err)
+	}
+	if tc := got.Suites[0].TestCases[0]; tc.SystemOut == nil || *tc.SystemOut != output {
+		t.Errorf("SystemOut = %v, want %q written from Output", tc.SystemOut, output)
+	}
+}
+
+func TestAddTestCaseCounts(t *testing.T) {
+	suite := TestSuite{}
+	suite.AddTestCase(*newTestCase("TestGood", Passed))
+	suite.AddTestCase(*newTestCase("TestBad", Failed))
+	suite.AddTestCase(*newTestCase("TestSkip", Skipped))
+	suite.AddTestCase(TestCase{Name: "TestError", Error: NewResult("panic")})
+	suite.AddTestCase(TestCase{Name: "TestDisabled", Status: "disabled"})
+	if suite.Tests != 5 || suite.Failures != 1 || suite.Errors != 1 || suite.Skipped != 1 || suite.Disabled != 1 {
+		t.Errorf("counts = %d tests, %d failures, %d errors, %d skipped, %d disabled, want 5, 1, 1, 1, 1",
+			suite.Tests, suite.Failures, suite.Errors, suite.Skipped, suite.Disabled)
+	}
+}
+
 func TestAddTestSuite(t *testing.T) {
 	testSuites := TestSuites{}
 	testSuite0 := TestSuite{Name: "suite_0"}
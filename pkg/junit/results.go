@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// results.go merges, filters and compares sets of junit results
+
+package junit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+)
+
+// TestID identifies a test case across sets of results
+type TestID struct {
+	Suite string
+	Name  string
+}
+
+func (id TestID) String() string {
+	return fmt.Sprintf("%s.%s", id.Suite, id.Name)
+}
+
+// ReadFile reads a junit file, see UnMarshal
+func ReadFile(path string) (*TestSuites, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	testSuites, err := UnMarshal(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing junit file %q: %v", path, err)
+	}
+	return testSuites, nil
+}
+
+// Merge merges sets of results into one, where suites with the same name are
+// combined. The result of a test case in a later set replaces the result of
+// the same test case in an earlier set, i.e. the result of a rerun replaces
+// the first result. Suites and test cases keep the order they first appeared
+// in, and suites keep the attributes of their first appearance.
+func Merge(sets ...*TestSuites) *TestSuites {
+	merged := &TestSuites{}
+	suiteIndexes := make(map[string]int)
+	caseIndexes := make(map[TestID]int)
+	for _, set := range sets {
+		if set == nil {
+			continue
+		}
+		for _, suite := range set.Suites {
+			i, ok := suiteIndexes[suite.Name]
+			if !ok {
+				i = len(merged.Suites)
+				suiteIndexes[suite.Name] = i
+				merged.Suites = append(merged.Suites, suite.withoutTestCases())
+			}
+			ms := &merged.Suites[i]
+			for _, tc := range suite.TestCases {
+				id := TestID{Suite: suite.Name, Name: tc.Name}
+				if j, ok := caseIndexes[id]; ok {
+					ms.TestCases[j] = tc
+					continue
+				}
+				caseIndexes[id] = len(ms.TestCases)
+				ms.TestCases = append(ms.TestCases, tc)
+			}
+		}
+	}
+	for i := range merged.Suites {
+		merged.Suites[i].updateCounts()
+	}
+	return merged
+}
+
+// withoutTestCases returns a copy of the suite with its attributes, output
+// and properties, but no test cases
+func (ts *TestSuite) withoutTestCases() TestSuite {
+	res := *ts
+	res.TestCases = nil
+	return res
+}
+
+// updateCounts sets the count of tests, failures, errors, skipped and disabled
+// tests of the suite from its test cases
+func (ts *TestSuite) updateCounts() {
+	ts.Tests = len(ts.TestCases)
+	ts.Failures, ts.Errors, ts.Skipped, ts.Disabled = 0, 0, 0, 0
+	for i := range ts.TestCases {
+		ts.countTestCase(&ts.TestCases[i])
+	}
+}
+
+// TestCaseFilter selects test cases of a suite
+type TestCaseFilter func(suite *TestSuite, tc *TestCase) bool
+
+// StatusFilter selects the test cases with any of the statuses
+func StatusFilter(statuses ...TestStatusEnum) TestCaseFilter {
+	return func(_ *TestSuite, tc *TestCase) bool {
+		status := tc.GetTestStatus()
+		for _, s := range statuses {
+			if s == status {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NameFilter selects the test cases with a name matching re
+func NameFilter(re *regexp.Regexp) TestCaseFilter {
+	return func(_ *TestSuite, tc *TestCase) bool {
+		return re.MatchString(tc.Name)
+	}
+}
+
+// SuiteFilter selects the test cases of the suites with a name matching re
+func SuiteFilter(re *regexp.Regexp) TestCaseFilter {
+	return func(suite *TestSuite, _ *TestCase) bool {
+		return re.MatchString(suite.Name)
+	}
+}
+
+// Filter returns the test cases selected by all filters, suites left without
+// test cases are dropped
+func Filter(testSuites *TestSuites, filters ...TestCaseFilter) *TestSuites {
+	filtered := &TestSuites{}
+	for i := range testSuites.Suites {
+		suite := &testSuites.Suites[i]
+		fs := suite.withoutTestCases()
+		for j := range suite.TestCases {
+			if matchesAll(suite, &suite.TestCases[j], filters) {
+				fs.TestCases = append(fs.TestCases, suite.TestCases[j])
+			}
+		}
+		if len(fs.TestCases) > 0 {
+			fs.updateCounts()
+			filtered.Suites = append(filtered.Suites, fs)
+		}
+	}
+	return filtered
+}
+
+func matchesAll(suite *TestSuite, tc *TestCase, filters []TestCaseFilter) bool {
+	for _, filter := range filters {
+		if !filter(suite, tc) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffResult lists the test cases whose status changed between two sets of
+// results, sorted by suite and name
+type DiffResult struct {
+	// NewFailures failed in the new results, and didn't fail or didn't run
+	// in the base results
+	NewFailures []TestID
+	// Fixed failed in the base results, and passed in the new results
+	Fixed []TestID
+	// NewlySkipped passed or failed in the base results, and were skipped in
+	// the new results
+	NewlySkipped []TestID
+}
+
+// Empty reports if no test case changed
+func (d *DiffResult) Empty() bool {
+	return len(d.NewFailures) == 0 && len(d.Fixed) == 0 && len(d.NewlySkipped) == 0
+}
+
+// statuses returns the status of each test case of the results
+func statuses(testSuites *TestSuites) map[TestID]TestStatusEnum {
+	res := make(map[TestID]TestStatusEnum)
+	for _, suite := range testSuites.Suites {
+		for i := range suite.TestCases {
+			res[TestID{Suite: suite.Name, Name: suite.TestCases[i].Name}] = suite.TestCases[i].GetTestStatus()
+		}
+	}
+	return res
+}
+
+// Diff compares the results of head with the base results
+func Diff(base, head *TestSuites) *DiffResult {
+	baseStatuses := statuses(base)
+	d := &DiffResult{}
+	for id, status := range statuses(head) {
+		baseStatus, ran := baseStatuses[id]
+		switch {
+		case status == Failed && baseStatus != Failed:
+			d.NewFailures = append(d.NewFailures, id)
+		case status == Passed && baseStatus == Failed:
+			d.Fixed = append(d.Fixed, id)
+		case status == Skipped && ran && baseStatus != Skipped:
+			d.NewlySkipped = append(d.NewlySkipped, id)
+		}
+	}
+	for _, ids := range [][]TestID{d.NewFailures, d.Fixed, d.NewlySkipped} {
+		sort.Slice(ids, func(i, j int) bool {
+			if ids[i].Suite != ids[j].Suite {
+				return ids[i].Suite < ids[j].Suite
+			}
+			return ids[i].Name < ids[j].Name
+		})
+	}
+	return d
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package junit
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func newTestSuites(suites map[string][]*TestCase, order ...string) *TestSuites {
+	testSuites := &TestSuites{}
+	for _, name := range order {
+		suite := TestSuite{Name: name}
+		for _, tc := range suites[name] {
+			suite.AddTestCase(*tc)
+		}
+		testSuites.Suites = append(testSuites.Suites, suite)
+	}
+	return testSuites
+}
+
+// summary returns the status of all test cases, and the counts of tests and
+// failures of all suites
+func summary(testSuites *TestSuites) []string {
+	var res []string
+	for _, suite := range testSuites.Suites {
+		for _, tc := range suite.TestCases {
+			res = append(res, suite.Name+"."+tc.Name+"="+string(tc.GetTestStatus()))
+		}
+		res = append(res, fmt.Sprintf("%s %d tests %d failures", suite.Name, suite.Tests, suite.Failures))
+	}
+	return res
+}
+
+func TestMerge(t *testing.T) {
+	first := newTestSuites(map[string][]*TestCase{
+		"a": {newTestCase("TestA1", Passed), newTestCase("TestA2", Failed)},
+		"b": {newTestCase("TestB1", Passed)},
+	}, "a", "b")
+	rerun := newTestSuites(map[string][]*TestCase{
+		"a": {newTestCase("TestA2", Passed)},
+		"c": {newTestCase("TestC1", Skipped)},
+	}, "a", "c")
+
+	got := summary(Merge(first, nil, rerun))
+	want := []string{
+		"a.TestA1=passed", "a.TestA2=passed", "a 2 tests 0 failures",
+		"b.TestB1=passed", "b 1 tests 0 failures",
+		"c.TestC1=skipped", "c 1 tests 0 failures",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+	if got := Merge(); len(got.Suites) != 0 {
+		t.Errorf("Merge() of nothing = %v, want no suites", got)
+	}
+}
+
+func TestMergeDisabled(t *testing.T) {
+	first, err := UnMarshal([]byte(`<testsuite name="a" tests="2" disabled="1">
+	<testcase name="TestA1" status="disabled"><skipped/></testcase>
+	<testcase name="TestA2"><failure>boom</failure></testcase>
+</testsuite>`))
+	if err != nil {
+		t.Fatalf("UnMarshal() = %v", err)
+	}
+	rerun := newTestSuites(map[string][]*TestCase{
+		"a": {newTestCase("TestA2", Passed)},
+	}, "a")
+
+	merged := Merge(first, rerun)
+	if a := merged.Suites[0]; a.Tests != 2 || a.Failures != 0 || a.Disabled != 1 || a.Skipped != 0 {
+		t.Errorf("Merge() suite has %d tests, %d failures, %d disabled and %d skipped, want 2, 0, 1 and 0",
+			a.Tests, a.Failures, a.Disabled, a.Skipped)
+	}
+	filtered := Filter(merged, StatusFilter(Skipped))
+	if a := filtered.Suites[0]; a.Tests != 1 || a.Disabled != 1 {
+		t.Errorf("Filter() suite has %d tests and %d disabled, want 1 and 1", a.Tests, a.Disabled)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	testSuites := newTestSuites(map[string][]*TestCase{
+		"e2e":  {newTestCase("TestAutoscale", Failed), newTestCase("TestAutoscale/sub", Failed), newTestCase("TestRoute", Passed)},
+		"unit": {newTestCase("TestParse", Skipped)},
+	}, "e2e", "unit")
+
+	tests := []struct {
+		name    string
+		filters []TestCaseFilter
+		want    []string
+	}{{
+		name:    "no filter",
+		filters: nil,
+		want:    summary(testSuites),
+	}, {
+		name:    "status",
+		filters: []TestCaseFilter{StatusFilter(Failed, Skipped)},
+		want:    []string{"e2e.TestAutoscale=failed", "e2e.TestAutoscale/sub=failed", "e2e 2 tests 2 failures", "unit.TestParse=skipped", "unit 1 tests 0 failures"},
+	}, {
+		name:    "status and name",
+		filters: []TestCaseFilter{StatusFilter(Failed), NameFilter(regexp.MustCompile(`^TestAutoscale$`))},
+		want:    []string{"e2e.TestAutoscale=failed", "e2e 1 tests 1 failures"},
+	}, {
+		name:    "suite",
+		filters: []TestCaseFilter{SuiteFilter(regexp.MustCompile(`unit`))},
+		want:    []string{"unit.TestParse=skipped", "unit 1 tests 0 failures"},
+	}, {
+		name:    "no match",
+		filters: []TestCaseFilter{NameFilter(regexp.MustCompile(`TestNothing`))},
+		want:    nil,
+	}}
+	for _, test := range tests {
+		if got := summary(Filter(testSuites, test.filters...)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Filter() with %s = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	base := newTestSuites(map[string][]*TestCase{
+		"e2e": {
+			newTestCase("TestStillPasses", Passed),
+			newTestCase("TestStillFails", Failed),
+			newTestCase("TestFixed", Failed),
+			newTestCase("TestBroken", Passed),
+			newTestCase("TestNowSkipped", Failed),
+			newTestCase("TestStillSkipped", Skipped),
+			newTestCase("TestRemoved", Failed),
+		},
+	}, "e2e")
+	head := newTestSuites(map[string][]*TestCase{
+		"e2e": {
+			newTestCase("TestStillPasses", Passed),
+			newTestCase("TestStillFails", Failed),
+			newTestCase("TestFixed", Passed),
+			newTestCase("TestBroken", Failed),
+			newTestCase("TestNowSkipped", Skipped),
+			newTestCase("TestStillSkipped", Skipped),
+			newTestCase("TestNewSkipped", Skipped),
+		},
+		"new": {
+			newTestCase("TestNewFails", Failed),
+		},
+	}, "e2e", "new")
+
+	got := Diff(base, head)
+	want := &DiffResult{
+		NewFailures:  []TestID{{"e2e", "TestBroken"}, {"new", "TestNewFails"}},
+		Fixed:        []TestID{{"e2e", "TestFixed"}},
+		NewlySkipped: []TestID{{"e2e", "TestNowSkipped"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+	if !Diff(base, base).Empty() {
+		t.Errorf("Diff() of the same results is not empty")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// stream.go decodes large junit files test case by test case
+
+package junit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// TestCaseFunc is called by Stream with each test case and its suite, the
+// suite has no test cases. Returning an error stops the stream.
+type TestCaseFunc func(suite *TestSuite, tc *TestCase) error
+
+// Stream decodes junit results from r token by token and calls fn with each
+// test case, so that only a test case is held in memory at a time instead of
+// the whole file as with UnMarshal. Like UnMarshal, r may hold <testsuites/>
+// or a single <testsuite/>, and nested suites are supported. The suite passed
+// to fn has the attributes of its <testsuite/> element and the properties
+// that come before the test case, while the system-out and system-err of
+// suites are skipped as they can be large. Empty input has no test cases.
+func Stream(r io.Reader, fn TestCaseFunc) error {
+	d := xml.NewDecoder(r)
+	var suites []*TestSuite // the suites being decoded, the innermost last
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "testsuite" {
+				suite, err := decodeSuiteAttrs(t)
+				if err != nil {
+					return err
+				}
+				suites = append(suites, suite)
+				continue
+			}
+			if len(suites) == 0 {
+				continue // <testsuites/>, or an unknown root element
+			}
+			suite := suites[len(suites)-1]
+			switch t.Name.Local {
+			case "testcase":
+				var tc TestCase
+				if err := d.DecodeElement(&tc, &t); err != nil {
+					return err
+				}
+				if err := fn(suite, &tc); err != nil {
+					return err
+				}
+			case "properties":
+				var properties TestProperties
+				if err := d.DecodeElement(&properties, &t); err != nil {
+					return err
+				}
+				suite.Properties.Properties = append(suite.Properties.Properties, properties.Properties...)
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "testsuite" && len(suites) > 0 {
+				suites = suites[:len(suites)-1]
+			}
+		}
+	}
+}
+
+// decodeSuiteAttrs creates a suite with the attributes of a <testsuite/>
+// element, decoded with the tags of TestSuite
+func decodeSuiteAttrs(start xml.StartElement) (*TestSuite, error) {
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	// Only the attributes without namespace are attributes of TestSuite
+	start.Name.Space = ""
+	var attrs []xml.Attr
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	start.Attr = attrs
+	if err := e.EncodeToken(start); err != nil {
+		return nil, err
+	}
+	if err := e.EncodeToken(start.End()); err != nil {
+		return nil, err
+	}
+	if err := e.Flush(); err != nil {
+		return nil, err
+	}
+	var suite TestSuite
+	if err := xml.Unmarshal(buf.Bytes(), &suite); err != nil {
+		return nil, fmt.Errorf("failed decoding attributes of suite: %v", err)
+	}
+	return &suite, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package junit
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// streamAll collects the suites and test cases streamed from s
+func streamAll(s string) ([]TestSuite, error) {
+	var suites []TestSuite
+	err := Stream(strings.NewReader(s), func(suite *TestSuite, tc *TestCase) error {
+		if len(suites) == 0 || suites[len(suites)-1].Name != suite.Name {
+			suites = append(suites, *suite)
+		}
+		suites[len(suites)-1].TestCases = append(suites[len(suites)-1].TestCases, *tc)
+		return nil
+	})
+	return suites, err
+}
+
+func TestStream(t *testing.T) {
+	for _, s := range []string{validSuiteString, validSuitesString, fullSchemaString} {
+		want, err := UnMarshal([]byte(s))
+		if err != nil {
+			t.Fatalf("UnMarshal() = %v", err)
+		}
+		got, err := streamAll(s)
+		if err != nil {
+			t.Fatalf("Stream() = %v", err)
+		}
+		if len(got) != len(want.Suites) {
+			t.Fatalf("Stream() got %d suites, want %d", len(got), len(want.Suites))
+		}
+		for i := range got {
+			// Suite outputs are skipped, and properties come before the test
+			// cases in the schema
+			want.Suites[i].XMLName = got[i].XMLName
+			want.Suites[i].SystemOut, want.Suites[i].SystemErr = nil, nil
+			want.Suites[i].Properties = got[i].Properties
+			if !reflect.DeepEqual(got[i], want.Suites[i]) {
+				t.Errorf("Stream() suite = %+v, want %+v", got[i], want.Suites[i])
+			}
+		}
+	}
+}
+
+func TestStreamProperties(t *testing.T) {
+	suites, err := streamAll(validSuiteString)
+	if err != nil {
+		t.Fatalf("Stream() = %v", err)
+	}
+	want := TestProperties{Properties: []TestProperty{{Name: "go.version", Value: "go1.6"}}}
+	if !reflect.DeepEqual(suites[0].Properties, want) {
+		t.Errorf("Properties = %+v, want %+v", suites[0].Properties, want)
+	}
+}
+
+func TestStreamNestedSuites(t *testing.T) {
+	s := `<testsuites>
+	<testsuite name="outer">
+		<testcase name="TestA"/>
+		<testsuite name="inner">
+			<testcase name="TestB"/>
+		</testsuite>
+		<testcase name="TestC"/>
+	</testsuite>
+</testsuites>`
+	var got []string
+	err := Stream(strings.NewReader(s), func(suite *TestSuite, tc *TestCase) error {
+		got = append(got, TestID{Suite: suite.Name, Name: tc.Name}.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream() = %v", err)
+	}
+	want := []string{"outer.TestA", "inner.TestB", "outer.TestC"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Stream() = %v, want %v", got, want)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	if _, err := streamAll(""); err != nil {
+		t.Errorf("Stream() of empty input = %v, want no error", err)
+	}
+	if _, err := streamAll(malSuitesString); err == nil {
+		t.Error("Stream() of malformed input = nil, want an error")
+	}
+	errStop := errors.New("stop")
+	calls := 0
+	err := Stream(strings.NewReader(validSuitesString), func(*TestSuite, *TestCase) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("Stream() = %v after %d calls, want %v after 1 call", err, calls, errStop)
+	}
+}
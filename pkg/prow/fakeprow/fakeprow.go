@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// fakeprow.go fakes the storage of prow jobs for testing purpose
+
+package fakeprow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"knative.dev/test-infra/pkg/prow"
+)
+
+// FakeStorage is an in-memory storage, implements all functions of
+// prow.Storage
+type FakeStorage struct {
+	Files map[string][]byte // map of path: contents
+}
+
+var _ prow.Storage = (*FakeStorage)(nil)
+
+// NewFakeStorage creates a FakeStorage and initialize its map
+func NewFakeStorage() *FakeStorage {
+	return &FakeStorage{Files: make(map[string][]byte)}
+}
+
+// AddFile adds a file, p is relative to the root of the bucket
+func (fs *FakeStorage) AddFile(p string, contents []byte) {
+	fs.Files[path.Clean(p)] = contents
+}
+
+// AddBuild adds the started.json and finished.json of a build of a job, and
+// returns the storage path of the build. The build isn't finished if
+// finishTime is 0.
+func (fs *FakeStorage) AddBuild(job *prow.Job, buildID int, startTime, finishTime int64) string {
+	storagePath := path.Join(job.StoragePath, strconv.Itoa(buildID))
+	started, _ := json.Marshal(prow.Started{Timestamp: startTime})
+	fs.AddFile(path.Join(storagePath, prow.StartedJSON), started)
+	if finishTime != 0 {
+		finished, _ := json.Marshal(prow.Finished{Timestamp: finishTime, Passed: true})
+		fs.AddFile(path.Join(storagePath, prow.FinishedJSON), finished)
+	}
+	return storagePath
+}
+
+// dirPrefix returns the prefix of the paths of the children of a directory
+func dirPrefix(dir string) string {
+	dir = strings.Trim(dir, "/")
+	if dir == "" || dir == "." {
+		return ""
+	}
+	return dir + "/"
+}
+
+// Exists checks if a file or a directory exists
+func (fs *FakeStorage) Exists(p string) bool {
+	if _, ok := fs.Files[path.Clean(p)]; ok {
+		return true
+	}
+	prefix := dirPrefix(p)
+	for filePath := range fs.Files {
+		if strings.HasPrefix(filePath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadFile reads a file
+func (fs *FakeStorage) ReadFile(p string) ([]byte, error) {
+	contents, ok := fs.Files[path.Clean(p)]
+	if !ok {
+		return nil, fmt.Errorf("file %q not found", p)
+	}
+	return contents, nil
+}
+
+// NewReader opens a file for reading
+func (fs *FakeStorage) NewReader(p string) (io.ReadCloser, error) {
+	contents, err := fs.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(contents)), nil
+}
+
+// ListChildrenFiles recursively lists all files under a directory, sorted
+func (fs *FakeStorage) ListChildrenFiles(dir string) ([]string, error) {
+	prefix := dirPrefix(dir)
+	var files []string
+	for filePath := range fs.Files {
+		if strings.HasPrefix(filePath, prefix) {
+			files = append(files, filePath)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ListDirectChildren lists the files and directories directly under a
+// directory, sorted
+func (fs *FakeStorage) ListDirectChildren(dir string) ([]string, error) {
+	prefix := dirPrefix(dir)
+	seen := make(map[string]bool)
+	var children []string
+	for filePath := range fs.Files {
+		if !strings.HasPrefix(filePath, prefix) {
+			continue
+		}
+		child := prefix + strings.SplitN(strings.TrimPrefix(filePath, prefix), "/", 2)[0]
+		if !seen[child] {
+			seen[child] = true
+			children = append(children, child)
+		}
+	}
+	sort.Strings(children)
+	return children, nil
+}
@@ -23,6 +23,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"io"
 	"log"
 	"os"
 	"path"
@@ -73,6 +74,8 @@ type Job struct {
 	StoragePath string  // optional
 	PullID      int     // only for Presubmit jobs
 	Builds      []Build // optional
+
+	storage Storage // where the files of the job are read from
 }
 
 // Build points to a build stored under a particular gcs path.
@@ -83,6 +86,8 @@ type Build struct {
 	Bucket      string // optional
 	StartTime   *int64
 	FinishTime  *int64
+
+	storage Storage // where the files of the build are read from
 }
 
 // Started holds the started.json values of the build.
@@ -132,15 +137,24 @@ func Initialize() error {
 	return err
 }
 
-// NewJob creates new job struct
+// NewJob creates new job struct, reading the files of the job from GCS with
+// the client created by Initialize
 // pullID is only saved by Presubmit job for determining StoragePath
 func NewJob(jobName, jobType, orgName, repoName string, pullID int) *Job {
+	return NewJobWithStorage(nil, jobName, jobType, orgName, repoName, pullID)
+}
+
+// NewJobWithStorage creates new job struct, reading the files of the job and
+// of its builds from storage, or from GCS with the client created by
+// Initialize if storage is nil
+func NewJobWithStorage(storage Storage, jobName, jobType, orgName, repoName string, pullID int) *Job {
 	job := Job{
-		Name:   jobName,
-		Type:   jobType,
-		Bucket: BucketName,
-		Org:    orgName,
-		Repo:   repoName,
+		Name:    jobName,
+		Type:    jobType,
+		Bucket:  BucketName,
+		Org:     orgName,
+		Repo:    repoName,
+		storage: storage,
 	}
 
 	switch jobType {
@@ -159,13 +173,20 @@ func NewJob(jobName, jobType, orgName, repoName string, pullID int) *Job {
 
 // PathExists checks if the storage path of a job exists in gcs or not
 func (j *Job) PathExists() bool {
-	return client.Exists(ctx, BucketName, j.StoragePath)
+	return j.getStorage().Exists(j.StoragePath)
+}
+
+func (j *Job) getStorage() Storage {
+	if j.storage == nil {
+		return defaultStorage()
+	}
+	return j.storage
 }
 
 // GetLatestBuildNumber gets the latest build number for job
 func (j *Job) GetLatestBuildNumber() (int, error) {
 	logFilePath := path.Join(j.StoragePath, Latest)
-	contents, err := client.ReadObject(ctx, BucketName, logFilePath)
+	contents, err := j.getStorage().ReadFile(logFilePath)
 	if err != nil {
 		return 0, err
 	}
@@ -184,6 +205,7 @@ func (j *Job) NewBuild(buildID int) *Build {
 		JobName:     j.Name,
 		StoragePath: path.Join(j.StoragePath, strconv.Itoa(buildID)),
 		BuildID:     buildID,
+		storage:     j.storage,
 	}
 
 	if startTime, err := build.GetStartTime(); err == nil {
@@ -224,7 +246,7 @@ func (j *Job) GetBuilds() []Build {
 // for job, keeps the ones that can be parsed as integer
 func (j *Job) GetBuildIDs() []int {
 	var buildIDs []int
-	gcsBuildPaths, _ := client.ListDirectChildren(ctx, j.Bucket, j.StoragePath)
+	gcsBuildPaths, _ := j.getStorage().ListDirectChildren(j.StoragePath)
 	for _, gcsBuildPath := range gcsBuildPaths {
 		if buildID, err := getBuildIDFromBuildPath(gcsBuildPath); err == nil {
 			buildIDs = append(buildIDs, buildID)
@@ -255,21 +277,28 @@ func (j *Job) GetLatestBuilds(count int) []Build {
 	return builds[:count]
 }
 
+func (b *Build) getStorage() Storage {
+	if b.storage == nil {
+		return defaultStorage()
+	}
+	return b.storage
+}
+
 // IsStarted check if build has started by looking at "started.json" file
 func (b *Build) IsStarted() bool {
-	return client.Exists(ctx, BucketName, path.Join(b.StoragePath, StartedJSON))
+	return b.getStorage().Exists(path.Join(b.StoragePath, StartedJSON))
 }
 
 // IsFinished check if build has finished by looking at "finished.json" file
 func (b *Build) IsFinished() bool {
-	return client.Exists(ctx, BucketName, path.Join(b.StoragePath, FinishedJSON))
+	return b.getStorage().Exists(path.Join(b.StoragePath, FinishedJSON))
 }
 
 // GetStartTime gets started timestamp of a build,
 // returning -1 if the build didn't start or if it failed to get the timestamp
 func (b *Build) GetStartTime() (int64, error) {
 	var started Started
-	if err := unmarshalJSONFile(path.Join(b.StoragePath, StartedJSON), &started); err != nil {
+	if err := unmarshalJSONFile(b.getStorage(), path.Join(b.StoragePath, StartedJSON), &started); err != nil {
 		return -1, err
 	}
 	return started.Timestamp, nil
@@ -279,7 +308,7 @@ func (b *Build) GetStartTime() (int64, error) {
 // returning -1 if the build didn't finish or if it failed to get the timestamp
 func (b *Build) GetFinishTime() (int64, error) {
 	var finished Finished
-	if err := unmarshalJSONFile(path.Join(b.StoragePath, FinishedJSON), &finished); err != nil {
+	if err := unmarshalJSONFile(b.getStorage(), path.Join(b.StoragePath, FinishedJSON), &finished); err != nil {
 		return -1, err
 	}
 	return finished.Timestamp, nil
@@ -287,7 +316,7 @@ func (b *Build) GetFinishTime() (int64, error) {
 
 // GetArtifacts gets gcs path for all artifacts of current build
 func (b *Build) GetArtifacts() []string {
-	artifacts, _ := client.ListChildrenFiles(ctx, BucketName, b.GetArtifactsDir())
+	artifacts, _ := b.getStorage().ListChildrenFiles(b.GetArtifactsDir())
 	return artifacts
 }
 
@@ -304,7 +333,15 @@ func (b *Build) GetBuildLogPath() string {
 // ReadFile reads given file of current build,
 // relPath is the file path relative to build directory
 func (b *Build) ReadFile(relPath string) ([]byte, error) {
-	return client.ReadObject(ctx, BucketName, path.Join(b.StoragePath, relPath))
+	return b.getStorage().ReadFile(path.Join(b.StoragePath, relPath))
+}
+
+// NewReader opens given file of current build for reading, so that large
+// files don't have to be read at once.
+// relPath is the file path relative to build directory.
+// Important: caller must call Close on the returned Reader when done reading
+func (b *Build) NewReader(relPath string) (io.ReadCloser, error) {
+	return b.getStorage().NewReader(path.Join(b.StoragePath, relPath))
 }
 
 // ParseLog parses the build log and returns the lines where the checkLog func does not return an empty slice,
@@ -312,7 +349,7 @@ func (b *Build) ReadFile(relPath string) ([]byte, error) {
 func (b *Build) ParseLog(checkLog func(s []string) *string) ([]string, error) {
 	var logs []string
 
-	f, err := client.NewReader(ctx, b.Bucket, b.GetBuildLogPath())
+	f, err := b.getStorage().NewReader(b.GetBuildLogPath())
 	if err != nil {
 		return logs, err
 	}
@@ -332,10 +369,10 @@ func getBuildIDFromBuildPath(buildPath string) (int, error) {
 	return strconv.Atoi(buildIDStr)
 }
 
-// unmarshalJSONFile reads a file from gcs, parses it with xml and write to v.
+// unmarshalJSONFile reads a file from storage, parses it with json and write to v.
 // v must be an arbitrary struct, slice, or string.
-func unmarshalJSONFile(storagePath string, v interface{}) error {
-	contents, err := client.ReadObject(ctx, BucketName, storagePath)
+func unmarshalJSONFile(storage Storage, storagePath string, v interface{}) error {
+	contents, err := storage.ReadFile(storagePath)
 	if err != nil {
 		return err
 	}
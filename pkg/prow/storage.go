@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// storage.go defines where the files of prow jobs are read from
+
+package prow
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"knative.dev/test-infra/pkg/gcs"
+)
+
+// Storage reads the files of prow jobs, laid out like the knative-prow
+// bucket. All paths are slash separated and relative to the root of the
+// bucket, i.e. "logs/<job>/<build>/started.json".
+type Storage interface {
+	// Exists checks if a file or a directory exists
+	Exists(p string) bool
+	// ReadFile reads a file
+	ReadFile(p string) ([]byte, error)
+	// NewReader opens a file for reading.
+	// Important: caller must call Close on the returned Reader when done reading
+	NewReader(p string) (io.ReadCloser, error)
+	// ListChildrenFiles recursively lists all files under a directory
+	ListChildrenFiles(dir string) ([]string, error)
+	// ListDirectChildren lists the files and directories directly under a
+	// directory
+	ListDirectChildren(dir string) ([]string, error)
+}
+
+// gcsStorage reads the files of prow jobs from a GCS bucket
+type gcsStorage struct {
+	client gcs.Client
+	bucket string
+}
+
+// NewGCSStorage creates a Storage reading from a GCS bucket, i.e. BucketName
+func NewGCSStorage(client gcs.Client, bucket string) Storage {
+	return &gcsStorage{client: client, bucket: bucket}
+}
+
+// defaultStorage is the storage of jobs and builds created without one, it
+// reads BucketName with the client created by Initialize
+func defaultStorage() Storage {
+	return NewGCSStorage(client, BucketName)
+}
+
+// Exists implements Storage
+func (g *gcsStorage) Exists(p string) bool {
+	return g.client.Exists(ctx, g.bucket, p)
+}
+
+// ReadFile implements Storage
+func (g *gcsStorage) ReadFile(p string) ([]byte, error) {
+	return g.client.ReadObject(ctx, g.bucket, p)
+}
+
+// NewReader implements Storage
+func (g *gcsStorage) NewReader(p string) (io.ReadCloser, error) {
+	return g.client.NewReader(ctx, g.bucket, p)
+}
+
+// ListChildrenFiles implements Storage
+func (g *gcsStorage) ListChildrenFiles(dir string) ([]string, error) {
+	return g.client.ListChildrenFiles(ctx, g.bucket, dir)
+}
+
+// ListDirectChildren implements Storage
+func (g *gcsStorage) ListDirectChildren(dir string) ([]string, error) {
+	return g.client.ListDirectChildren(ctx, g.bucket, dir)
+}
+
+// localStorage reads the files of prow jobs from a local directory mirroring
+// the bucket, i.e. a copy of gs://knative-prow/logs/<job> in <dir>/logs/<job>
+type localStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates a Storage reading from a local directory mirroring
+// the layout of the bucket, so that tools can run against fixtures without
+// GCS credentials
+func NewLocalStorage(dir string) Storage {
+	return &localStorage{dir: dir}
+}
+
+func (l *localStorage) localPath(p string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(p))
+}
+
+// Exists implements Storage
+func (l *localStorage) Exists(p string) bool {
+	_, err := os.Stat(l.localPath(p))
+	return err == nil
+}
+
+// ReadFile implements Storage
+func (l *localStorage) ReadFile(p string) ([]byte, error) {
+	return ioutil.ReadFile(l.localPath(p))
+}
+
+// NewReader implements Storage
+func (l *localStorage) NewReader(p string) (io.ReadCloser, error) {
+	return os.Open(l.localPath(p))
+}
+
+// ListChildrenFiles implements Storage. Like in GCS, a directory that
+// doesn't exist has no children.
+func (l *localStorage) ListChildrenFiles(dir string) ([]string, error) {
+	var files []string
+	root := l.localPath(dir)
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == root {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, path.Join(dir, filepath.ToSlash(rel)))
+		return nil
+	})
+	return files, err
+}
+
+// ListDirectChildren implements Storage. Like in GCS, a directory that
+// doesn't exist has no children.
+func (l *localStorage) ListDirectChildren(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(l.localPath(dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	children := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		children = append(children, path.Join(strings.TrimRight(dir, "/"), entry.Name()))
+	}
+	return children, nil
+}
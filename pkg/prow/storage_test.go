@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prow
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata mirrors the knative-prow bucket with builds 1 and 2 finished, and
+// build 3 started
+const testBucketDir = "testdata"
+
+func TestLocalStorageJob(t *testing.T) {
+	job := NewJobWithStorage(NewLocalStorage(testBucketDir), testJobName, PeriodicJob, orgName, repoName, 0)
+	if !job.PathExists() {
+		t.Fatalf("PathExists() = false, want true")
+	}
+	if missing := NewJobWithStorage(NewLocalStorage(testBucketDir), "missing", PeriodicJob, orgName, repoName, 0); missing.PathExists() {
+		t.Errorf("PathExists() of a missing job = true, want false")
+	}
+	if latest, err := job.GetLatestBuildNumber(); err != nil || latest != 3 {
+		t.Errorf("GetLatestBuildNumber() = %d, %v, want 3", latest, err)
+	}
+	if ids := job.GetBuildIDs(); !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+		t.Errorf("GetBuildIDs() = %v, want [1 2 3]", ids)
+	}
+
+	var ids []int
+	for _, b := range job.GetLatestBuilds(5) {
+		ids = append(ids, b.BuildID)
+	}
+	if want := []int{2, 1}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("GetLatestBuilds() = %v, want %v", ids, want)
+	}
+
+	build := job.NewBuild(3)
+	if !build.IsStarted() || build.IsFinished() || build.StartTime == nil || *build.StartTime != 1700007200 || build.FinishTime != nil {
+		t.Errorf("NewBuild(3) = %+v, want a started build", build)
+	}
+}
+
+func TestLocalStorageBuild(t *testing.T) {
+	job := NewJobWithStorage(NewLocalStorage(testBucketDir), testJobName, PeriodicJob, orgName, repoName, 0)
+	build := job.NewBuild(1)
+	want := []string{
+		"logs/job_0/1/artifacts/e2e/junit_2.xml",
+		"logs/job_0/1/artifacts/junit_1.xml",
+	}
+	if artifacts := build.GetArtifacts(); !reflect.DeepEqual(artifacts, want) {
+		t.Errorf("GetArtifacts() = %v, want %v", artifacts, want)
+	}
+	if artifacts := job.NewBuild(3).GetArtifacts(); len(artifacts) != 0 {
+		t.Errorf("GetArtifacts() of a build without artifacts = %v, want none", artifacts)
+	}
+
+	contents, err := build.ReadFile("artifacts/junit_1.xml")
+	if err != nil || !strings.Contains(string(contents), "testsuites") {
+		t.Errorf("ReadFile() = %q, %v, want the junit file", contents, err)
+	}
+	r, err := build.NewReader("artifacts/junit_1.xml")
+	if err != nil {
+		t.Fatalf("NewReader() = %v", err)
+	}
+	defer r.Close()
+	if streamed, err := ioutil.ReadAll(r); err != nil || string(streamed) != string(contents) {
+		t.Errorf("NewReader() read %q, %v, want %q", streamed, err, contents)
+	}
+
+	logs, err := build.ParseLog(func(s []string) *string {
+		if len(s) > 0 && s[0] == "ERROR" {
+			msg := strings.Join(s[1:], " ")
+			return &msg
+		}
+		return nil
+	})
+	if want := []string{"something broke"}; err != nil || !reflect.DeepEqual(logs, want) {
+		t.Errorf("ParseLog() = %v, %v, want %v", logs, err, want)
+	}
+}
+
+func TestLocalStorageListDirectChildren(t *testing.T) {
+	s := NewLocalStorage(testBucketDir)
+	children, err := s.ListDirectChildren("logs/job_0/2/")
+	if want := []string{"logs/job_0/2/artifacts", "logs/job_0/2/finished.json", "logs/job_0/2/started.json"}; err != nil || !reflect.DeepEqual(children, want) {
+		t.Errorf("ListDirectChildren() = %v, %v, want %v", children, err, want)
+	}
+	if children, err := s.ListDirectChildren("logs/missing"); err != nil || len(children) != 0 {
+		t.Errorf("ListDirectChildren() of a missing directory = %v, %v, want none", children, err)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quarantine reads and writes skip lists of flaky tests. Skip lists
+// are generated by flaky-test-reporter, and Go tests skip the quarantined
+// tests by calling SkipIfQuarantined.
+package quarantine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PathEnv is the environment variable set to the path of the skip list
+	// read by SkipIfQuarantined
+	PathEnv = "QUARANTINE_FILE"
+	// RunQuarantinedEnv is the environment variable set to true to run
+	// quarantined tests anyway, i.e. so that they can be seen passing again
+	RunQuarantinedEnv = "RUN_QUARANTINED_TESTS"
+
+	header = "# Generated by flaky-test-reporter, tests listed here are skipped until\n" +
+		"# they expire. See https://github.com/knative/test-infra/tree/main/tools/flaky-test-reporter\n"
+)
+
+// Entry is a quarantined test
+type Entry struct {
+	// Suite is the junit suite of the test, usually its Go package. It's
+	// informational, as tests don't know their suite.
+	Suite string `json:"suite,omitempty"`
+	// Test is a regular expression matching the full name of the test, as
+	// returned by testing.T.Name()
+	Test string `json:"test"`
+	// Expires is when the test stops being skipped
+	Expires time.Time `json:"expires"`
+	// Issue is the link to the issue tracking the flakiness of the test
+	Issue string `json:"issue,omitempty"`
+}
+
+// List is a skip list of quarantined tests
+type List struct {
+	Tests []Entry `json:"tests"`
+}
+
+// TestPattern returns the Test pattern matching exactly the test name
+func TestPattern(name string) string {
+	return "^" + regexp.QuoteMeta(name) + "$"
+}
+
+// Parse parses a skip list, empty contents are an empty list
+func Parse(contents []byte) (*List, error) {
+	l := &List{}
+	if err := yaml.Unmarshal(contents, l); err != nil {
+		return nil, err
+	}
+	for _, e := range l.Tests {
+		if _, err := regexp.Compile(e.Test); err != nil {
+			return nil, fmt.Errorf("invalid test pattern %q: %v", e.Test, err)
+		}
+	}
+	return l, nil
+}
+
+// Load reads the skip list at path
+func Load(path string) (*List, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(contents)
+}
+
+// Marshal converts the skip list to YAML, sorted by suite and test so that
+// the changes of the file are easy to review
+func (l *List) Marshal() ([]byte, error) {
+	tests := append([]Entry{}, l.Tests...)
+	sort.Slice(tests, func(i, j int) bool {
+		if tests[i].Suite != tests[j].Suite {
+			return tests[i].Suite < tests[j].Suite
+		}
+		return tests[i].Test < tests[j].Test
+	})
+	contents, err := yaml.Marshal(&List{Tests: tests})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), contents...), nil
+}
+
+// Match returns the entry matching the test name that is not expired at
+// now, or nil if the test is not quarantined
+func (l *List) Match(name string, now time.Time) *Entry {
+	for i, e := range l.Tests {
+		if !now.Before(e.Expires) {
+			continue
+		}
+		if matched, _ := regexp.MatchString(e.Test, name); matched {
+			return &l.Tests[i]
+		}
+	}
+	return nil
+}
+
+// SkipIfQuarantined skips the test if it's quarantined in the skip list set
+// by the QUARANTINE_FILE environment variable, unless RUN_QUARANTINED_TESTS
+// is true
+func SkipIfQuarantined(t testing.TB) {
+	path := os.Getenv(PathEnv)
+	if path == "" {
+		return
+	}
+	if run, _ := strconv.ParseBool(os.Getenv(RunQuarantinedEnv)); run {
+		return
+	}
+	l, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed reading skip list %q: %v", path, err)
+	}
+	if e := l.Match(t.Name(), time.Now()); e != nil {
+		t.Skipf("Test is quarantined until %s as it's flaky, see %s", e.Expires.Format("2006-01-02"), e.Issue)
+	}
+}
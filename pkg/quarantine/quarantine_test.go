@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quarantine
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestMarshalParse(t *testing.T) {
+	l := &List{Tests: []Entry{
+		{Suite: "e2e", Test: TestPattern("TestB"), Expires: now, Issue: "https://github.com/org/repo/issues/2"},
+		{Suite: "conformance", Test: TestPattern("TestA/sub.case"), Expires: now},
+	}}
+	contents, err := l.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	if !strings.HasPrefix(string(contents), "# Generated by flaky-test-reporter") {
+		t.Errorf("Marshal() = %q, want a header comment", contents)
+	}
+	got, err := Parse(contents)
+	if err != nil {
+		t.Fatalf("Parse() = %v", err)
+	}
+	want := []Entry{l.Tests[1], l.Tests[0]}
+	if !reflect.DeepEqual(got.Tests, want) {
+		t.Errorf("Parse() = %v, want %v sorted by suite", got.Tests, want)
+	}
+
+	if empty, err := Parse(nil); err != nil || len(empty.Tests) != 0 {
+		t.Errorf("Parse(nil) = %v, %v, want an empty list", empty, err)
+	}
+	if _, err := Parse([]byte("tests:\n- test: '('\n")); err == nil {
+		t.Error("Parse() with an invalid pattern = nil, want error")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	l := &List{Tests: []Entry{
+		{Test: TestPattern("TestFlaky"), Expires: now.Add(time.Hour)},
+		{Test: "^TestSub/", Expires: now.Add(time.Hour)},
+		{Test: TestPattern("TestExpired"), Expires: now},
+	}}
+	tests := map[string]bool{
+		"TestFlaky":      true,
+		"TestFlakyOther": false,
+		"TestSub/case":   true,
+		"TestExpired":    false,
+	}
+	for name, want := range tests {
+		if got := l.Match(name, now) != nil; got != want {
+			t.Errorf("Match(%q) = %t, want %t", name, got, want)
+		}
+	}
+}
+
+func TestSkipIfQuarantined(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quarantine.yaml")
+	l := &List{Tests: []Entry{{Test: "^TestSkipIfQuarantined/quarantined$", Expires: time.Now().Add(time.Hour)}}}
+	contents, err := l.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(PathEnv, path)
+
+	var ran []string
+	for _, name := range []string{"quarantined", "other"} {
+		t.Run(name, func(t *testing.T) {
+			SkipIfQuarantined(t)
+			ran = append(ran, name)
+		})
+	}
+	if want := []string{"other"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+
+	t.Setenv(RunQuarantinedEnv, "true")
+	t.Run("quarantined", func(t *testing.T) {
+		SkipIfQuarantined(t)
+		ran = append(ran, "quarantined")
+	})
+	if len(ran) != 2 {
+		t.Errorf("quarantined test didn't run with %s=true", RunQuarantinedEnv)
+	}
+}
@@ -45,8 +45,144 @@ type JobConfig struct {
 	Type          string         `yaml:"type"`
 	IssueRepo     string         `yaml:"issueRepo,omitempty"`
 	SlackChannels []SlackChannel `yaml:"slackChannels,omitempty"`
+	// Source is where the test results are read from, one of "prow" (default),
+	// "local" or "github-actions"
+	Source string `yaml:"source,omitempty"`
+	// Path is the directory of the builds, for the "local" source, or a local
+	// mirror of the knative-prow bucket, for the "prow" source
+	Path string `yaml:"path,omitempty"`
+	// Workflow is the workflow file name (i.e. "e2e.yaml") and Branch the
+	// branch of its runs, for the "github-actions" source
+	Workflow string `yaml:"workflow,omitempty"`
+	Branch   string `yaml:"branch,omitempty"`
+	// Flakiness holds the thresholds for scoring flakiness of tests
+	Flakiness FlakinessConfig `yaml:"flakiness,omitempty"`
+	// Quarantine is the skip list of flaky tests maintained in the repo
+	Quarantine *QuarantineConfig `yaml:"quarantine,omitempty"`
+	// Issues holds the settings of the lifecycle of Github issues
+	Issues IssuesConfig `yaml:"issues,omitempty"`
+	// MentionOwners mentions the approvers of the OWNERS file closest to a
+	// flaky test in the issue created for it
+	MentionOwners bool `yaml:"mentionOwners,omitempty"`
 }
 
+// QuarantineConfig defines the skip list of flaky tests of a repo, updated
+// through pull requests
+type QuarantineConfig struct {
+	// File is the path of the skip list in the repo
+	File string `yaml:"file"`
+	// Branch is the branch the pull requests are opened against, "main" by
+	// default
+	Branch string `yaml:"branch,omitempty"`
+	// Days is the count of days flaky tests are quarantined for, 14 by
+	// default
+	Days int `yaml:"days,omitempty"`
+	// RunQuarantinedJob is the name of a job of the same repo running with
+	// RUN_QUARANTINED_TESTS=true. Quarantined tests are skipped by the other
+	// jobs, so they are only removed from the skip list before they expire
+	// once they pass in this job.
+	RunQuarantinedJob string `yaml:"runQuarantinedJob,omitempty"`
+}
+
+// FlakinessConfig holds the thresholds for scoring flakiness of tests of a
+// job, zero values are replaced by the defaults
+type FlakinessConfig struct {
+	// HalfLife is the number of builds after which the weight of a result is
+	// halved, so that recent results weigh more
+	HalfLife float64 `yaml:"halfLife,omitempty"`
+	// ClusterRatio is the ratio of failed tests above which the failures of a
+	// build are clustered, if at least ClusterMinFailures tests failed.
+	// Clustered failures are weighed by ClusterWeight, as they are more likely
+	// caused by the build than by the tests
+	ClusterRatio       float64 `yaml:"clusterRatio,omitempty"`
+	ClusterMinFailures int     `yaml:"clusterMinFailures,omitempty"`
+	ClusterWeight      float64 `yaml:"clusterWeight,omitempty"`
+	// OutageRatio is the ratio of failed tests above which a build is an
+	// infrastructure outage, if at least OutageMinFailures tests failed.
+	// Outage builds are excluded from the results of tests
+	OutageRatio       float64 `yaml:"outageRatio,omitempty"`
+	OutageMinFailures int     `yaml:"outageMinFailures,omitempty"`
+	// Confidence is the confidence level of the interval of the failure rate
+	Confidence float64 `yaml:"confidence,omitempty"`
+	// FlakyLowerBound is the lower bound of the failure rate interval above
+	// which a test that passed at least once is flaky
+	FlakyLowerBound float64 `yaml:"flakyLowerBound,omitempty"`
+	// FailingLowerBound is the lower bound of the failure rate interval above
+	// which a test is consistently failing
+	FailingLowerBound float64 `yaml:"failingLowerBound,omitempty"`
+	// RecoveredBuilds is the number of latest builds a test that failed
+	// before has to pass in to be recovered
+	RecoveredBuilds int `yaml:"recoveredBuilds,omitempty"`
+}
+
+// WithDefaults returns the config with the defaults for zero values
+func (fc FlakinessConfig) WithDefaults() FlakinessConfig {
+	if fc.HalfLife == 0 {
+		fc.HalfLife = 10
+	}
+	if fc.ClusterRatio == 0 {
+		fc.ClusterRatio = 0.5
+	}
+	if fc.ClusterMinFailures == 0 {
+		fc.ClusterMinFailures = 3
+	}
+	if fc.ClusterWeight == 0 {
+		fc.ClusterWeight = 0.25
+	}
+	if fc.OutageRatio == 0 {
+		fc.OutageRatio = 0.5
+	}
+	if fc.OutageMinFailures == 0 {
+		fc.OutageMinFailures = 5
+	}
+	if fc.Confidence == 0 {
+		fc.Confidence = 0.95
+	}
+	if fc.FlakyLowerBound == 0 {
+		fc.FlakyLowerBound = 0.01
+	}
+	if fc.FailingLowerBound == 0 {
+		fc.FailingLowerBound = 0.5
+	}
+	if fc.RecoveredBuilds == 0 {
+		fc.RecoveredBuilds = 3
+	}
+	return fc
+}
+
+// IssuesConfig defines the lifecycle of the Github issues of flaky tests of a
+// job, zero values are replaced by the defaults
+type IssuesConfig struct {
+	// StaleDays is the number of days after which the open issue of a test
+	// that didn't run in any scanned build is closed, as the test was likely
+	// removed
+	StaleDays int `yaml:"staleDays,omitempty"`
+	// RenameSimilarity is the min similarity, between 0 and 1, of the name of
+	// a new flaky test with the name of a test that no longer runs, for the
+	// issue of the latter to be reused as the test was likely renamed
+	RenameSimilarity float64 `yaml:"renameSimilarity,omitempty"`
+}
+
+// WithDefaults returns the config with the defaults for zero values
+func (ic IssuesConfig) WithDefaults() IssuesConfig {
+	if ic.StaleDays == 0 {
+		ic.StaleDays = 30
+	}
+	if ic.RenameSimilarity == 0 {
+		ic.RenameSimilarity = 0.8
+	}
+	return ic
+}
+
+const (
+	// ProwSource reads junit artifacts of Prow builds from GCS
+	ProwSource = "prow"
+	// LocalSource reads junit files from a local directory of builds
+	LocalSource = "local"
+	// GithubActionsSource reads junit files from GitHub Actions artifacts
+	GithubActionsSource = "github-actions"
+)
+
 // SlackChannel contains Slack channels info
 type SlackChannel struct {
 	Name     string `yaml:"name"`
@@ -22,4 +22,6 @@ const (
 	// Don't do anything if found more than 5 tests flaky, or 1% tests flaky, whichever comes first
 	countThreshold   = 5
 	percentThreshold = 0.01
+	// Maximum number of pages of workflow runs read to find the latest builds of a Github Actions job
+	maxRunPages = 10
 )
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// digest.go creates the weekly digest of flaky tests from the history store
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/test-infra/tools/flaky-test-reporter/history"
+)
+
+const (
+	digestPeriod = 7 * 24 * time.Hour
+	// maxDigestTests is the max count of tests listed per section of the
+	// digest
+	maxDigestTests = 20
+)
+
+// weeklyDigest compares the flaky tests of a job in the last week with the
+// ones of the week before
+type weeklyDigest struct {
+	New        []string // flaky this week but not the week before
+	Fixed      []string // flaky the week before, and only passed this week
+	Persistent []string // flaky both weeks
+}
+
+// getWeeklyDigest computes the digest of the job of rd as of now
+func getWeeklyDigest(store history.Store, rd RepoData, now time.Time) (*weeklyDigest, error) {
+	q := history.Query{Repo: rd.Config.Repo, Job: rd.Config.Name, Since: now.Add(-digestPeriod)}
+	thisWeek, err := store.Records(q)
+	if err != nil {
+		return nil, err
+	}
+	q.Since, q.Until = now.Add(-2*digestPeriod), now.Add(-digestPeriod)
+	lastWeek, err := store.Records(q)
+	if err != nil {
+		return nil, err
+	}
+
+	flakyLastWeek := sets.NewString()
+	for _, s := range history.Summarize(lastWeek) {
+		if s.Flaky() {
+			flakyLastWeek.Insert(s.Test)
+		}
+	}
+	// Summaries are sorted by test, so are the lists of the digest
+	d := &weeklyDigest{}
+	for _, s := range history.Summarize(thisWeek) {
+		switch {
+		case s.Flaky() && flakyLastWeek.Has(s.Test):
+			d.Persistent = append(d.Persistent, s.Test)
+		case s.Flaky():
+			d.New = append(d.New, s.Test)
+		case s.Runs > 0 && s.Failures == 0 && flakyLastWeek.Has(s.Test):
+			d.Fixed = append(d.Fixed, s.Test)
+		}
+	}
+	return d, nil
+}
+
+// createDigestSection lists tests of a section of the digest, along with
+// their issues
+func createDigestSection(title string, tests []string, rd RepoData, flakyIssuesMap map[string][]flakyIssue) string {
+	if len(tests) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%s (%d):", title, len(tests))
+	for i, testFullName := range tests {
+		if i == maxDigestTests {
+			message += fmt.Sprintf("\n>- and %d more", len(tests)-maxDigestTests)
+			break
+		}
+		message += fmt.Sprintf("\n>- %s", testFullName)
+		if flakyIssues, ok := flakyIssuesMap[getIdentityForTest(testFullName, rd.Config.Repo, rd.Config.Name)]; ok && rd.Config.IssueRepo != "" {
+			for _, fi := range flakyIssues {
+				message += fmt.Sprintf("\t%s", fi.issue.GetHTMLURL())
+			}
+		}
+	}
+	return message
+}
+
+// createDigestMessageForRepo creates the weekly digest slack message of the
+// job of rd
+func createDigestMessageForRepo(store history.Store, rd RepoData, flakyIssuesMap map[string][]flakyIssue, now time.Time) (string, error) {
+	d, err := getWeeklyDigest(store, rd, now)
+	if err != nil {
+		return "", fmt.Errorf("failed creating digest of job '%s' in repo '%s': %v", rd.Config.Name, rd.Config.Repo, err)
+	}
+	message := fmt.Sprintf("Weekly digest of flaky tests in '%s' from repo '%s', as of %s",
+		rd.Config.Name, rd.Config.Repo, now.Format("2006-01-02"))
+	if len(d.New)+len(d.Fixed)+len(d.Persistent) == 0 {
+		return message + "\nNo flaky tests in the last two weeks", nil
+	}
+	message += createDigestSection("New flaky tests", d.New, rd, flakyIssuesMap)
+	message += createDigestSection("Persistent flaky tests", d.Persistent, rd, flakyIssuesMap)
+	message += createDigestSection("Fixed flaky tests", d.Fixed, rd, flakyIssuesMap)
+	return message, nil
+}
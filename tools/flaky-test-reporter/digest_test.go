@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+	"knative.dev/test-infra/tools/flaky-test-reporter/history"
+)
+
+func TestWeeklyDigest(t *testing.T) {
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	rd := RepoData{Config: config.JobConfig{Name: "ci-serving-continuous", Repo: "serving"}}
+	var records []history.Record
+	add := func(test string, daysAgo int, statuses ...string) {
+		for _, status := range statuses {
+			records = append(records, history.Record{
+				Repo:      rd.Config.Repo,
+				Job:       rd.Config.Name,
+				Test:      test,
+				BuildID:   len(records),
+				Status:    status,
+				BuildTime: now.AddDate(0, 0, -daysAgo),
+			})
+		}
+	}
+	add("TestNew", 10, history.Passed, history.Passed)
+	add("TestNew", 2, history.Passed, history.Failed)
+	add("TestFixed", 10, history.Passed, history.Failed)
+	add("TestFixed", 2, history.Passed, history.Passed)
+	add("TestPersistent", 10, history.Passed, history.Failed)
+	add("TestPersistent", 2, history.Failed, history.Passed)
+	// Not run this week, so not known to be fixed
+	add("TestNotRun", 10, history.Passed, history.Failed)
+	// Flaky more than two weeks ago
+	add("TestOld", 20, history.Passed, history.Failed)
+	add("TestOld", 2, history.Passed, history.Passed)
+
+	store := history.NewFileStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err := store.Add(records); err != nil {
+		t.Fatalf("Add() = %v", err)
+	}
+	// Other jobs are left out
+	if err := store.Add([]history.Record{{Repo: "serving", Job: "other", Test: "TestOther", BuildID: 1, Status: history.Failed, BuildTime: now.AddDate(0, 0, -1)},
+		{Repo: "serving", Job: "other", Test: "TestOther", BuildID: 2, Status: history.Passed, BuildTime: now.AddDate(0, 0, -1)}}); err != nil {
+		t.Fatalf("Add() = %v", err)
+	}
+
+	got, err := getWeeklyDigest(store, rd, now)
+	if err != nil {
+		t.Fatalf("getWeeklyDigest() = %v", err)
+	}
+	want := &weeklyDigest{
+		New:        []string{"TestNew"},
+		Fixed:      []string{"TestFixed"},
+		Persistent: []string{"TestPersistent"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getWeeklyDigest() = %+v, want %+v", got, want)
+	}
+
+	message, err := createDigestMessageForRepo(store, rd, nil, now)
+	if err != nil {
+		t.Fatalf("createDigestMessageForRepo() = %v", err)
+	}
+	for _, want := range []string{"New flaky tests (1):\n>- TestNew", "Persistent flaky tests (1):\n>- TestPersistent", "Fixed flaky tests (1):\n>- TestFixed"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("createDigestMessageForRepo() = %q, want it to contain %q", message, want)
+		}
+	}
+}
+
+func TestCreateDigestSection(t *testing.T) {
+	var tests []string
+	for i := 0; i < maxDigestTests+3; i++ {
+		tests = append(tests, "Test")
+	}
+	message := createDigestSection("New flaky tests", tests, RepoData{}, nil)
+	if got, want := strings.Count(message, ">- Test"), maxDigestTests; got != want {
+		t.Errorf("createDigestSection() lists %d tests, want %d", got, want)
+	}
+	if !strings.HasSuffix(message, ">- and 3 more") {
+		t.Errorf("createDigestSection() = %q, want it to end with the count of tests left out", message)
+	}
+	if got := createDigestSection("Fixed flaky tests", nil, RepoData{}, nil); got != "" {
+		t.Errorf("createDigestSection() of no tests = %q, want empty", got)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"knative.dev/test-infra/pkg/ghutil"
 	"knative.dev/test-infra/pkg/helpers"
 	"knative.dev/test-infra/pkg/junit"
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
 )
 
 const (
@@ -63,6 +64,7 @@ const (
 ### Auto-generated issue tracking flakiness of test
 * **Test name**: %s
 * **Repository name**: %s
+* **Job name**: %s%s
 
 <!-------------End of issue body, Please don't edit below this line------------->
 <!--%s-->`
@@ -100,8 +102,15 @@ type flakyIssue struct {
 	comment  *github.IssueComment // The first auto comment, updated for every history
 }
 
-// getIdentityForTest creates a unique string for a test, which will be used for identifying Github issue
-func getIdentityForTest(testFullName, repoName string) string {
+// getIdentityForTest creates a unique string for a test of a job, which will be used for identifying Github issue.
+// Each job has its own issue for a test, so that its results are not mixed with those of other jobs.
+func getIdentityForTest(testFullName, repoName, jobName string) string {
+	return fmt.Sprintf("'%s' in repo '%s' job '%s'", testFullName, repoName, jobName)
+}
+
+// getRepoIdentityForTest creates the identity of the issues created before issues were per job, shared by all jobs
+// of the repo
+func getRepoIdentityForTest(testFullName, repoName string) string {
 	return fmt.Sprintf("'%s' in repo '%s'", testFullName, repoName)
 }
 
@@ -115,6 +124,11 @@ func getBulkIssueIdentity(rd RepoData, flakyRate float32) string {
 type GithubIssueHandler struct {
 	user   *github.User
 	client ghutil.GithubOperations
+	owners *ownersResolver // created on first use
+	// seenTests are the tests that ran in each job, keys are repo and job
+	seenTests map[string]map[string]sets.String
+	// actions are all actions taken on issues, for the reconciliation report
+	actions []issueAction
 }
 
 // Setup creates the necessary setup to make calls to work with github issues
@@ -131,6 +145,27 @@ func Setup(githubToken string) (*GithubIssueHandler, error) {
 	return &GithubIssueHandler{user: ghUser, client: ghc}, nil
 }
 
+// createOwnersContent mentions the approvers of the test in the issue body,
+// if the job is configured to. Failing to find them doesn't stop the issue
+// from being created.
+func (gih *GithubIssueHandler) createOwnersContent(rd RepoData, ts *TestStat) string {
+	if !rd.Config.MentionOwners {
+		return ""
+	}
+	if gih.owners == nil {
+		gih.owners = newOwnersResolver(gih.client)
+	}
+	approvers, err := gih.owners.getApprovers(rd.Config.Org, rd.Config.Repo, ts)
+	if err != nil {
+		log.Printf("failed finding owners of test in repo '%s': %v", rd.Config.Repo, err)
+		return ""
+	}
+	if len(approvers) == 0 {
+		return ""
+	}
+	return "\n* **Owners**: " + mentionUsers(approvers)
+}
+
 // The Repo field of an github Issue could be empty, use URL is more reliable
 func getOrgRepoFromIssue(issue *github.Issue) (string, string) {
 	arr := strings.Split(*(issue.RepositoryURL), "/")
@@ -151,10 +186,20 @@ func (gih *GithubIssueHandler) createCommentForTest(rd RepoData, testFullName st
 		var buildIDContents []string
 		for _, buildID := range ts.Failed {
 			buildIDContents = append(buildIDContents,
-				fmt.Sprintf("[%d](%s%s/%d)", buildID, jobLogsURL, rd.Config.Name, buildID))
+				fmt.Sprintf("[%d](%s)", buildID, rd.getBuildURL(buildID)))
 		}
 		content += strings.Join(buildIDContents, ", ")
 	}
+	if ts.isFlakyInBuild() {
+		content += fmt.Sprintf("\nPassed only on retry in %d runs: ", len(ts.FlakyInBuild))
+		var buildIDContents []string
+		for _, buildID := range ts.FlakyInBuild {
+			buildIDContents = append(buildIDContents,
+				fmt.Sprintf("[%d](%s)", buildID, rd.getBuildURL(buildID)))
+		}
+		content += strings.Join(buildIDContents, ", ")
+	}
+	content += createFailureModesContent(rd, ts)
 	return content
 }
 
@@ -163,7 +208,7 @@ func (gih *GithubIssueHandler) createHistoryUnicode(rd RepoData, comment, testFu
 	currentUnicode := fmt.Sprintf("%s: ", time.Unix(*rd.LastBuildStartTime, 0).String())
 	resultSlice := rd.getResultSliceForTest(testFullName)
 	for i, buildID := range rd.BuildIDs {
-		url := fmt.Sprintf("%s%s/%d", jobLogsURL, rd.Config.Name, buildID)
+		url := rd.getBuildURL(buildID)
 		var statusUnicode string
 		switch resultSlice[i] {
 		case junit.Passed:
@@ -206,8 +251,8 @@ func (gih *GithubIssueHandler) createHistoryUnicode(rd RepoData, comment, testFu
 	return fmt.Sprintf(historyPattern, res)
 }
 
-// updateIssue adds comments to an existing issue, close an issue if test passed both in previous day and today,
-// reopens the issue if test becomes flaky while issue is closed.
+// updateIssue adds comments to an existing issue, close an issue if test passed both in previous day and today
+// or recovered, reopens the issue if test becomes flaky while issue is closed.
 func (gih *GithubIssueHandler) updateIssue(fi flakyIssue, newComment string, ts *TestStat, dryrun bool) error {
 	issue := fi.issue
 	org, repo := getOrgRepoFromIssue(issue)
@@ -217,15 +262,15 @@ func (gih *GithubIssueHandler) updateIssue(fi flakyIssue, newComment string, ts
 		switch latestStatus[1] {
 		case passedStatus:
 			passedLastTime = true
-		case flakyStatus, failedStatus, lackDataStatus:
+		case flakyStatus, flakyInBuildStatus, failedStatus, lackDataStatus, recoveredStatus:
 			// for now no action is needed
 		default:
 			return fmt.Errorf("invalid test status code found from issue '%s'", *issue.URL)
 		}
 	}
 
-	// Update comment unless test passed and issue closed
-	if !ts.isPassed() || issue.GetState() == string(ghutil.IssueOpenState) {
+	// Update comment unless test passed or recovered and issue closed
+	if !(ts.isPassed() || ts.isRecovered()) || issue.GetState() == string(ghutil.IssueOpenState) {
 		if err := helpers.Run(
 			"updating comment",
 			func() error {
@@ -238,6 +283,7 @@ func (gih *GithubIssueHandler) updateIssue(fi flakyIssue, newComment string, ts
 
 	if ts.isPassed() { // close open issue if the test passed twice consecutively
 		if issue.GetState() == string(ghutil.IssueOpenState) && passedLastTime {
+			gih.recordAction(closeAction, ts.TestName, issue.GetURL(), "passed in latest 2 scans")
 			if err := helpers.Run(
 				"closing issue",
 				func() error {
@@ -252,8 +298,26 @@ func (gih *GithubIssueHandler) updateIssue(fi flakyIssue, newComment string, ts
 				return fmt.Errorf("failed closing issue '%s': '%v'", *issue.URL, err)
 			}
 		}
+	} else if ts.isRecovered() { // close open issue if the test passed in the latest runs
+		if issue.GetState() == string(ghutil.IssueOpenState) {
+			gih.recordAction(closeAction, ts.TestName, issue.GetURL(), "recovered")
+			if err := helpers.Run(
+				"closing issue",
+				func() error {
+					closeErr := gih.client.CloseIssue(org, repo, *issue.Number)
+					if closeErr == nil {
+						closeComment := "Closing issue: this test has recovered, it passed in the latest runs"
+						_, closeErr = gih.client.CreateComment(org, repo, *issue.Number, closeComment)
+					}
+					return closeErr
+				},
+				dryrun); err != nil {
+				return fmt.Errorf("failed closing issue '%s': '%v'", *issue.URL, err)
+			}
+		}
 	} else if ts.isFlaky() { // reopen closed issue if test found flaky
 		if issue.GetState() == string(ghutil.IssueCloseState) {
+			gih.recordAction(reopenAction, ts.TestName, issue.GetURL(), "flaky again")
 			if err := helpers.Run(
 				"reopening issue",
 				func() error {
@@ -478,24 +542,57 @@ func (gih *GithubIssueHandler) processGithubIssuesForRepo(rd RepoData, flakyIssu
 		issues   []flakyIssue
 	)
 
-	// Update/Create issues for flaky/used-to-be-flaky tests
+	// Update/Create issues for flaky/used-to-be-flaky tests. Issues of other tests that ran are updated too, so that
+	// their auto comment tells when the tests last ran, see closeStaleIssues
 	for testFullName, ts := range rd.TestStats {
-		if !ts.isFlaky() && !ts.isPassed() {
+		identity := getIdentityForTest(testFullName, rd.Config.Repo, rd.Config.Name)
+		existIssues, ok := flakyIssuesMap[identity]
+		_, shared := flakyIssuesMap[getRepoIdentityForTest(testFullName, rd.Config.Repo)]
+		if !ts.isFlaky() && !ts.isPassed() && !ts.isRecovered() && !ts.isFlakyInBuild() && !ok && !shared {
 			continue
 		}
-		identity := getIdentityForTest(testFullName, rd.Config.Repo)
-		comment := gih.createCommentForTest(rd, testFullName)
-		if existIssues, ok := flakyIssuesMap[identity]; ok { // update issue with current result
+		if !ok && shared {
+			// the issue is shared by all jobs of the repo, the first job running the test takes it over
+			message := fmt.Sprintf("Moving issue for '%s' to job '%s'", testFullName, rd.Config.Name)
+			log.Println(message)
+			messages = append(messages, message)
+			if err := gih.moveRepoIssueToJob(rd, testFullName, flakyIssuesMap, dryrun); err != nil {
+				log.Println(err)
+				errs = append(errs, err)
+				continue
+			}
+			existIssues, ok = flakyIssuesMap[identity]
+		}
+		if !ok && ts.isFlaky() { // the test may have been renamed, reuse the issue of its former name
+			if oldIdentity := gih.findRenamedIssue(rd, testFullName, flakyIssuesMap); oldIdentity != "" {
+				message := fmt.Sprintf("Renaming issue for %s to '%s'", oldIdentity, testFullName)
+				log.Println(message)
+				messages = append(messages, message)
+				if err := gih.renameIssue(rd, oldIdentity, testFullName, flakyIssuesMap, dryrun); err != nil {
+					log.Println(err)
+					errs = append(errs, err)
+					continue
+				}
+				existIssues, ok = flakyIssuesMap[identity]
+			}
+		}
+		relatedIssues := createRelatedIssuesContent(testFullName, identity, flakyIssuesMap)
+		comment := gih.createCommentForTest(rd, testFullName) + relatedIssues
+		if ok { // update issue with current result
 			for _, existIssue := range existIssues {
 				if strings.Contains(existIssue.comment.GetBody(), comment) {
 					log.Printf("skip updating issue '%s', as it already contains data for run '%d'\n",
 						*existIssue.issue.URL, *rd.LastBuildStartTime)
 					continue
 				}
+				if relatedIssues != "" && !strings.Contains(existIssue.comment.GetBody(), relatedIssues) {
+					gih.recordAction(linkAction, testFullName, existIssue.issue.GetURL(), "same test tracked by other issues")
+				}
 				comment += gih.createHistoryUnicode(rd, existIssue.comment.GetBody(), testFullName)
 				message := fmt.Sprintf("Updating issue '%s' for '%s'", *existIssue.issue.URL, existIssue.identity)
 				log.Println(message)
 				messages = append(messages, message)
+				gih.recordAction(updateAction, testFullName, existIssue.issue.GetURL(), "latest result "+ts.getTestStatus())
 				if err := gih.updateIssue(existIssue, comment, ts, dryrun); err != nil {
 					log.Println(err)
 					errs = append(errs, err)
@@ -507,11 +604,13 @@ func (gih *GithubIssueHandler) processGithubIssuesForRepo(rd RepoData, flakyIssu
 			message := fmt.Sprintf("Creating issue '%s' in repo '%s'", testFullName, rd.Config.IssueRepo)
 			log.Println(message)
 			messages = append(messages, message)
+			gih.recordAction(createAction, testFullName, "", "flaky")
 			if issue, err := gih.createNewIssue(
 				rd.Config.Org,
 				rd.Config.IssueRepo,
 				fmt.Sprintf("[flaky] %s", testFullName),
-				fmt.Sprintf(issueBodyTemplate, testFullName, rd.Config.Repo, fmt.Sprintf(testIdentifierPattern, identity)),
+				fmt.Sprintf(issueBodyTemplate, testFullName, rd.Config.Repo, rd.Config.Name, gih.createOwnersContent(rd, ts),
+					fmt.Sprintf(testIdentifierPattern, identity)),
 				comment,
 				dryrun); err != nil {
 				log.Println(err)
@@ -528,12 +627,14 @@ func (gih *GithubIssueHandler) processGithubIssuesForRepo(rd RepoData, flakyIssu
 	return issues, messages, helpers.CombineErrors(errs)
 }
 
-// analyze all results, figure out flaky tests and processing existing auto:flaky issues
-func (gih *GithubIssueHandler) processGithubIssues(repoDataAll []RepoData, dryrun bool) (map[string][]flakyIssue, error) {
+// analyze all results, figure out flaky tests and processing existing auto:flaky issues.
+// unscannedJobs are the jobs whose results couldn't be collected, see closeStaleIssues
+func (gih *GithubIssueHandler) processGithubIssues(repoDataAll []RepoData, unscannedJobs []config.JobConfig, dryrun bool) (map[string][]flakyIssue, error) {
 	flakyGHIssuesMap, err := gih.getFlakyIssues(repoDataAll)
 	if err != nil {
 		log.Fatalf("%v", err)
 	}
+	gih.setSeenTests(repoDataAll)
 
 	// map repo to jobs, and jobs to messages
 	messagesMap := make(map[string]map[string][]string)
@@ -560,7 +661,15 @@ func (gih *GithubIssueHandler) processGithubIssues(repoDataAll []RepoData, dryru
 
 	gih.logSummary(repoDataAll, messagesMap, errMap)
 
-	return flakyGHIssuesMap, nil
+	_, closeErr := gih.closeStaleIssues(repoDataAll, unscannedJobs, flakyGHIssuesMap, time.Now(), dryrun)
+	if closeErr != nil {
+		log.Printf("Errors closing stale issues:\n%v", closeErr)
+	}
+	if err := gih.writeIssueActionsReport(dryrun); err != nil {
+		log.Printf("Failed writing issue actions report: %v", err)
+	}
+
+	return flakyGHIssuesMap, closeErr
 }
 
 // logSummary will log the overall summary for all repos and all jobs
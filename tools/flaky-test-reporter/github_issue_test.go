@@ -51,11 +51,19 @@ var testStatsMapForTest = map[string]TestStat{
 		Failed:   []int{},
 		Skipped:  []int{7, 8, 9},
 	},
+	"recovered": {
+		TestName: "a",
+		Passed:   []int{4, 5, 6, 7, 8, 9},
+		Failed:   []int{0, 1, 2, 3},
+		Skipped:  []int{},
+		Score:    &FlakinessScore{Classification: recoveredClass},
+	},
 }
 
 var (
 	fakeOrg    = "fakeorg"
 	fakeRepo   = "fakerepo"
+	fakeJob    = "fakejob"
 	fakeUserID = int64(99)
 	fakeUser   = &github.User{
 		ID: &fakeUserID,
@@ -82,6 +90,7 @@ func createNewIssue(fgih *GithubIssueHandler, title, body, testStat string) (*gi
 
 func createRepoData(passed, flaky, failed, notenoughdata int, issueRepo string, startTime int64) RepoData {
 	cfg := config.JobConfig{
+		Name:      fakeJob,
 		Repo:      fakeRepo,
 		IssueRepo: issueRepo,
 	}
@@ -162,16 +171,21 @@ func TestUpdateIssue(t *testing.T) {
 		{"open", testStatsMapForTest["failed"], true, true, "open", nil},
 		{"open", testStatsMapForTest["notenoughdata"], false, true, "open", nil},
 		{"open", testStatsMapForTest["notenoughdata"], true, true, "open", nil},
+		{"open", testStatsMapForTest["recovered"], false, true, "closed", nil},
+		{"open", testStatsMapForTest["recovered"], true, true, "closed", nil},
 		{"closed", testStatsMapForTest["flaky"], false, true, "open", nil},
 		{"closed", testStatsMapForTest["flaky"], true, true, "open", nil},
-		{"closed", testStatsMapForTest["passed"], false, true, "closed", nil},
-		{"closed", testStatsMapForTest["passed"], true, true, "closed", nil},
+		{"closed", testStatsMapForTest["passed"], false, false, "closed", nil},
+		{"closed", testStatsMapForTest["passed"], true, false, "closed", nil},
 		{"closed", testStatsMapForTest["failed"], false, true, "closed", nil},
 		{"closed", testStatsMapForTest["failed"], true, true, "closed", nil},
 		{"closed", testStatsMapForTest["notenoughdata"], false, true, "closed", nil},
 		{"closed", testStatsMapForTest["notenoughdata"], true, true, "closed", nil},
+		{"closed", testStatsMapForTest["recovered"], false, false, "closed", nil},
+		{"closed", testStatsMapForTest["recovered"], true, false, "closed", nil},
 	}
 
+	setBuildsCount(t, 10)
 	title := "fake title"
 	body := "fake body"
 
@@ -184,6 +198,9 @@ func TestUpdateIssue(t *testing.T) {
 		} else {
 			issue, comment = createNewIssue(fgih, title, body, "Flaky")
 		}
+		if data.issueState == "closed" {
+			fgih.client.CloseIssue(fakeOrg, fakeRepo, *issue.Number)
+		}
 		commentBody := comment.GetBody()
 
 		fi := flakyIssue{
@@ -209,5 +226,8 @@ func TestUpdateIssue(t *testing.T) {
 		if !data.appendComment && gotComment.GetBody() != commentBody {
 			t.Fatalf("update comment %v, got: '%s', want: '%s'", data, gotComment.GetBody(), commentBody)
 		}
+		if gotState := issue.GetState(); gotState != data.wantStatus {
+			t.Fatalf("update issue %v, got state: '%s', want: '%s'", data, gotState, data.wantStatus)
+		}
 	}
 }
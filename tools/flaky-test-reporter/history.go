@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// history.go records test results in the history store
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"knative.dev/test-infra/pkg/helpers"
+	"knative.dev/test-infra/pkg/mysql"
+	"knative.dev/test-infra/tools/flaky-test-reporter/history"
+)
+
+// historyFlags configures the history store, either a local file or a MySQL
+// database
+type historyFlags struct {
+	file         string
+	dbUserSecret string
+	dbPassSecret string
+	dbHostSecret string
+	dbPort       string
+	dbName       string
+}
+
+func addHistoryFlags(fs *flag.FlagSet) *historyFlags {
+	hf := &historyFlags{}
+	fs.StringVar(&hf.file, "history-file", "", "local file storing the history of test results")
+	fs.StringVar(&hf.dbUserSecret, "history-db-user", "", "file containing the MySQL user name of the history database")
+	fs.StringVar(&hf.dbPassSecret, "history-db-password", "", "file containing the MySQL password of the history database")
+	fs.StringVar(&hf.dbHostSecret, "history-db-host", "", "file containing the MySQL host of the history database")
+	fs.StringVar(&hf.dbPort, "history-db-port", "3306", "MySQL port of the history database")
+	fs.StringVar(&hf.dbName, "history-db-name", "flaky_tests", "name of the history database")
+	return hf
+}
+
+// newStore creates the configured history store, or returns nil if there is
+// none
+func (hf *historyFlags) newStore() (history.Store, error) {
+	switch {
+	case hf.file != "" && hf.dbHostSecret != "":
+		return nil, fmt.Errorf("only one of --history-file and --history-db-host can be set")
+	case hf.file != "":
+		return history.NewFileStore(hf.file), nil
+	case hf.dbHostSecret != "":
+		dbConfig, err := mysql.ConfigureDB(hf.dbUserSecret, hf.dbPassSecret, hf.dbHostSecret, hf.dbPort, hf.dbName)
+		if err != nil {
+			return nil, err
+		}
+		return history.NewMySQLStore(dbConfig)
+	}
+	return nil, nil
+}
+
+// getHistoryRecords converts the results of all tests of RepoData into
+// history records
+func getHistoryRecords(rd RepoData) []history.Record {
+	var records []history.Record
+	add := func(ts *TestStat, buildIDs []int, status string) {
+		for _, buildID := range buildIDs {
+			records = append(records, history.Record{
+				Repo:      rd.Config.Repo,
+				Job:       rd.Config.Name,
+				Test:      ts.TestName,
+				BuildID:   buildID,
+				Status:    status,
+				BuildTime: time.Unix(rd.BuildStartTimes[buildID], 0).UTC(),
+			})
+		}
+	}
+	for _, ts := range rd.TestStats {
+		add(ts, ts.Passed, history.Passed)
+		add(ts, ts.Failed, history.Failed)
+		add(ts, ts.Skipped, history.Skipped)
+	}
+	return records
+}
+
+// recordHistory adds the results of all jobs to the history store
+func recordHistory(store history.Store, repoDataAll []RepoData, dryrun bool) error {
+	var allErrs []error
+	for _, rd := range repoDataAll {
+		records := getHistoryRecords(rd)
+		if err := helpers.Run(
+			fmt.Sprintf("recording %d results of job '%s' in repo '%s'", len(records), rd.Config.Name, rd.Config.Repo),
+			func() error {
+				return store.Add(records)
+			},
+			dryrun,
+		); err != nil {
+			allErrs = append(allErrs, fmt.Errorf("failed recording results of job '%s' in repo '%s': %v", rd.Config.Name, rd.Config.Repo, err))
+		}
+	}
+	return helpers.CombineErrors(allErrs)
+}
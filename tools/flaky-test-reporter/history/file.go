@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileStore stores records in a local file, one JSON record per line. It's
+// meant for local runs and tests, MySQLStore is meant for production.
+type FileStore struct {
+	path string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore creates a FileStore writing to path, the file is created on
+// the first Add
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Add implements Store
+func (fs *FileStore) Add(records []Record) error {
+	existing, err := fs.readAll()
+	if err != nil {
+		return err
+	}
+	stored := make(map[key]bool, len(existing))
+	for _, r := range existing {
+		stored[r.key()] = true
+	}
+
+	f, err := os.OpenFile(fs.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if stored[r.key()] {
+			continue
+		}
+		stored[r.key()] = true
+		if err := enc.Encode(r); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Records implements Store
+func (fs *FileStore) Records(q Query) ([]Record, error) {
+	all, err := fs.readAll()
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, r := range all {
+		if q.matches(r) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// Close implements Store
+func (fs *FileStore) Close() error {
+	return nil
+}
+
+// readAll reads all records of the file, or none if it doesn't exist yet
+func (fs *FileStore) readAll() ([]Record, error) {
+	f, err := os.Open(fs.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("failed parsing line %d of '%s': %v", line, fs.path, err)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package history stores the results of tests across runs of
+// flaky-test-reporter, so that flakiness can be followed over a long period
+// rather than over the latest builds only.
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// Passed means the test passed in the build
+	Passed = "passed"
+	// Failed means the test failed in the build
+	Failed = "failed"
+	// Skipped means the test was skipped in the build
+	Skipped = "skipped"
+)
+
+// Record is the outcome of a test in a build
+type Record struct {
+	Repo      string    `json:"repo"`
+	Job       string    `json:"job"`
+	Test      string    `json:"test"`
+	BuildID   int       `json:"buildID"`
+	Status    string    `json:"status"`
+	BuildTime time.Time `json:"buildTime"` // start time of the build
+}
+
+// key identifies the outcome of a test in a build, as the same builds are
+// scanned by consecutive runs
+type key struct {
+	repo    string
+	job     string
+	test    string
+	buildID int
+}
+
+func (r Record) key() key {
+	return key{repo: r.Repo, job: r.Job, test: r.Test, buildID: r.BuildID}
+}
+
+// Query filters records, empty fields match all records
+type Query struct {
+	Repo  string
+	Job   string
+	Test  string
+	Since time.Time
+	Until time.Time // excluded
+}
+
+func (q Query) matches(r Record) bool {
+	return (q.Repo == "" || q.Repo == r.Repo) &&
+		(q.Job == "" || q.Job == r.Job) &&
+		(q.Test == "" || q.Test == r.Test) &&
+		!r.BuildTime.Before(q.Since) &&
+		(q.Until.IsZero() || r.BuildTime.Before(q.Until))
+}
+
+// Store records the outcomes of tests
+type Store interface {
+	// Add stores records, ignoring the ones already stored for the same
+	// test and build
+	Add(records []Record) error
+	// Records returns all records matching the query
+	Records(q Query) ([]Record, error)
+	// Close releases the resources of the store
+	Close() error
+}
+
+// TestSummary is the flakiness of a test over a set of records
+type TestSummary struct {
+	Repo     string  `json:"repo"`
+	Test     string  `json:"test"`
+	Runs     int     `json:"runs"` // passed or failed
+	Failures int     `json:"failures"`
+	Skipped  int     `json:"skipped"`
+	Rate     float64 `json:"failureRate"`
+}
+
+// Flaky reports if the test both passed and failed
+func (s TestSummary) Flaky() bool {
+	return s.Failures > 0 && s.Failures < s.Runs
+}
+
+// Summarize summarizes the records per repo and test, sorted by repo and test
+func Summarize(records []Record) []TestSummary {
+	type testKey struct{ repo, test string }
+	indexes := make(map[testKey]int)
+	var summaries []TestSummary
+	for _, r := range records {
+		k := testKey{repo: r.Repo, test: r.Test}
+		i, ok := indexes[k]
+		if !ok {
+			i = len(summaries)
+			indexes[k] = i
+			summaries = append(summaries, TestSummary{Repo: r.Repo, Test: r.Test})
+		}
+		switch r.Status {
+		case Passed:
+			summaries[i].Runs++
+		case Failed:
+			summaries[i].Runs++
+			summaries[i].Failures++
+		default:
+			summaries[i].Skipped++
+		}
+	}
+	for i := range summaries {
+		if summaries[i].Runs > 0 {
+			summaries[i].Rate = float64(summaries[i].Failures) / float64(summaries[i].Runs)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Repo != summaries[j].Repo {
+			return summaries[i].Repo < summaries[j].Repo
+		}
+		return summaries[i].Test < summaries[j].Test
+	})
+	return summaries
+}
+
+// Flakiest returns up to limit flaky tests of each repo, the highest failure
+// rate first. Tests that never passed are failing rather than flaky, and are
+// left out.
+func Flakiest(records []Record, limit int) []TestSummary {
+	var flaky []TestSummary
+	for _, s := range Summarize(records) {
+		if s.Flaky() {
+			flaky = append(flaky, s)
+		}
+	}
+	sort.SliceStable(flaky, func(i, j int) bool {
+		if flaky[i].Repo != flaky[j].Repo {
+			return flaky[i].Repo < flaky[j].Repo
+		}
+		if flaky[i].Rate != flaky[j].Rate {
+			return flaky[i].Rate > flaky[j].Rate
+		}
+		return flaky[i].Runs > flaky[j].Runs
+	})
+	var res []TestSummary
+	perRepo := make(map[string]int)
+	for _, s := range flaky {
+		if perRepo[s.Repo] < limit {
+			perRepo[s.Repo]++
+			res = append(res, s)
+		}
+	}
+	return res
+}
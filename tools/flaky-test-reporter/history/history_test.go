@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func record(repo, test string, buildID int, status string, daysAgo int) Record {
+	return Record{Repo: repo, Job: "continuous", Test: test, BuildID: buildID, Status: status, BuildTime: now.AddDate(0, 0, -daysAgo)}
+}
+
+func TestFileStore(t *testing.T) {
+	fs := NewFileStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	if records, err := fs.Records(Query{}); err != nil || len(records) != 0 {
+		t.Fatalf("Records() before Add = %v, %v, want none", records, err)
+	}
+
+	first := []Record{
+		record("serving", "e2e.TestA", 1, Passed, 100),
+		record("serving", "e2e.TestA", 2, Failed, 10),
+		record("eventing", "e2e.TestA", 2, Passed, 10),
+	}
+	if err := fs.Add(first); err != nil {
+		t.Fatalf("Add() = %v", err)
+	}
+	// Builds are scanned again by the next run
+	second := []Record{
+		record("serving", "e2e.TestA", 2, Failed, 10),
+		record("serving", "e2e.TestA", 3, Passed, 1),
+	}
+	if err := fs.Add(second); err != nil {
+		t.Fatalf("Add() = %v", err)
+	}
+
+	all, err := fs.Records(Query{})
+	if err != nil {
+		t.Fatalf("Records() = %v", err)
+	}
+	if len(all) != 4 {
+		t.Errorf("Records() = %d records, want 4 without duplicates", len(all))
+	}
+
+	got, err := fs.Records(Query{Repo: "serving", Test: "e2e.TestA", Since: now.AddDate(0, 0, -90)})
+	if err != nil {
+		t.Fatalf("Records() = %v", err)
+	}
+	want := []Record{first[1], second[1]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Records() = %v, want %v", got, want)
+	}
+
+	got, err = fs.Records(Query{Job: "continuous", Since: now.AddDate(0, 0, -14), Until: now.AddDate(0, 0, -7)})
+	if err != nil {
+		t.Fatalf("Records() = %v", err)
+	}
+	if want := []Record{first[1], first[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Records() of the previous week = %v, want %v", got, want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := []Record{
+		record("serving", "e2e.TestA", 1, Passed, 1),
+		record("serving", "e2e.TestA", 2, Failed, 1),
+		record("serving", "e2e.TestA", 3, Skipped, 1),
+		record("serving", "e2e.TestA", 4, Passed, 1),
+	}
+	want := []TestSummary{{Repo: "serving", Test: "e2e.TestA", Runs: 3, Failures: 1, Skipped: 1, Rate: 1.0 / 3}}
+	if got := Summarize(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize() = %v, want %v", got, want)
+	}
+}
+
+func TestFlakiest(t *testing.T) {
+	var records []Record
+	add := func(repo, test string, passed, failed int) {
+		for i := 0; i < passed; i++ {
+			records = append(records, record(repo, test, len(records), Passed, 1))
+		}
+		for i := 0; i < failed; i++ {
+			records = append(records, record(repo, test, len(records), Failed, 1))
+		}
+	}
+	add("serving", "TestRarelyFails", 9, 1)
+	add("serving", "TestOftenFails", 5, 5)
+	add("serving", "TestAlwaysFails", 0, 10)
+	add("serving", "TestAlwaysPasses", 10, 0)
+	add("serving", "TestSometimesFails", 8, 2)
+	add("eventing", "TestFails", 1, 1)
+
+	var got []string
+	for _, s := range Flakiest(records, 2) {
+		got = append(got, s.Repo+"/"+s.Test)
+	}
+	want := []string{"eventing/TestFails", "serving/TestOftenFails", "serving/TestSometimesFails"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flakiest() = %v, want %v", got, want)
+	}
+}
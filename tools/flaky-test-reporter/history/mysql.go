@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"knative.dev/test-infra/pkg/mysql"
+)
+
+const (
+	resultsTable = "test_results"
+
+	// Key columns are sized to fit in the max index length of InnoDB
+	createTableStmt = `CREATE TABLE IF NOT EXISTS ` + resultsTable + ` (
+	repo VARCHAR(100) NOT NULL,
+	job VARCHAR(200) NOT NULL,
+	test VARCHAR(400) NOT NULL,
+	build_id BIGINT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	build_time DATETIME NOT NULL,
+	PRIMARY KEY (repo, job, test, build_id),
+	INDEX (repo, build_time)
+)`
+	insertStmt = `INSERT IGNORE INTO ` + resultsTable +
+		` (repo, job, test, build_id, status, build_time) VALUES (?, ?, ?, ?, ?, ?)`
+	selectStmt = `SELECT repo, job, test, build_id, status, build_time FROM ` + resultsTable
+)
+
+// MySQLStore stores records in a MySQL table, created if it doesn't exist
+type MySQLStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*MySQLStore)(nil)
+
+// NewMySQLStore connects to the database of config and creates the results
+// table if needed
+func NewMySQLStore(config *mysql.DBConfig) (*MySQLStore, error) {
+	db, err := config.Connect()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createTableStmt); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed creating table '%s': %v", resultsTable, err)
+	}
+	return &MySQLStore{db: db}, nil
+}
+
+// Add implements Store
+func (ms *MySQLStore) Add(records []Record) error {
+	tx, err := ms.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(insertStmt)
+	if err != nil {
+		return mysql.RollbackTx(tx, err)
+	}
+	defer stmt.Close()
+	for _, r := range records {
+		if _, err := stmt.Exec(r.Repo, r.Job, r.Test, r.BuildID, r.Status, r.BuildTime.UTC()); err != nil {
+			return mysql.RollbackTx(tx, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Records implements Store
+func (ms *MySQLStore) Records(q Query) ([]Record, error) {
+	conditions := []string{"build_time >= ?"}
+	args := []interface{}{q.Since.UTC()}
+	if q.Repo != "" {
+		conditions = append(conditions, "repo = ?")
+		args = append(args, q.Repo)
+	}
+	if q.Job != "" {
+		conditions = append(conditions, "job = ?")
+		args = append(args, q.Job)
+	}
+	if q.Test != "" {
+		conditions = append(conditions, "test = ?")
+		args = append(args, q.Test)
+	}
+	if !q.Until.IsZero() {
+		conditions = append(conditions, "build_time < ?")
+		args = append(args, q.Until.UTC())
+	}
+	rows, err := ms.db.Query(selectStmt+" WHERE "+strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.Repo, &r.Job, &r.Test, &r.BuildID, &r.Status, &r.BuildTime); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// Close implements Store
+func (ms *MySQLStore) Close() error {
+	return ms.db.Close()
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+	"knative.dev/test-infra/tools/flaky-test-reporter/history"
+)
+
+func TestRecordAndQueryHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history.jsonl")
+	startTime := time.Now().Add(-time.Hour).Unix()
+	rd := RepoData{
+		Config:          config.JobConfig{Name: "continuous", Repo: "serving"},
+		BuildStartTimes: map[int]int64{1: startTime, 2: startTime, 3: startTime},
+		TestStats: map[string]*TestStat{
+			"e2e.TestFlaky":  {TestName: "e2e.TestFlaky", Passed: []int{3, 1}, Failed: []int{2}},
+			"e2e.TestPassed": {TestName: "e2e.TestPassed", Passed: []int{3, 2}, Skipped: []int{1}},
+		},
+	}
+	if got := len(getHistoryRecords(rd)); got != 6 {
+		t.Errorf("getHistoryRecords() = %d records, want 6", got)
+	}
+	if err := recordHistory(history.NewFileStore(file), []RepoData{rd}, false); err != nil {
+		t.Fatalf("recordHistory() = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runQuery([]string{"--history-file", file, "--top", "5"}, &out); err != nil {
+		t.Fatalf("runQuery() = %v", err)
+	}
+	if !strings.Contains(out.String(), "e2e.TestFlaky") || strings.Contains(out.String(), "e2e.TestPassed") {
+		t.Errorf("runQuery() flakiest tests = %q, want only e2e.TestFlaky", out.String())
+	}
+
+	out.Reset()
+	if err := runQuery([]string{"--history-file", file, "--test", "e2e.TestPassed", "--output", "json"}, &out); err != nil {
+		t.Fatalf("runQuery() = %v", err)
+	}
+	if !strings.Contains(out.String(), `"runs": 2`) || !strings.Contains(out.String(), `"skipped": 1`) {
+		t.Errorf("runQuery() for test = %q, want 2 runs and 1 skipped", out.String())
+	}
+
+	if err := runQuery(nil, &out); err == nil {
+		t.Error("runQuery() without store = nil, want error")
+	}
+}
@@ -0,0 +1,375 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// issue_lifecycle.go follows flaky tests that are renamed, removed or tracked
+// by several issues, and reports all actions taken on issues
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/test-infra/pkg/ghutil"
+	"knative.dev/test-infra/pkg/helpers"
+	"knative.dev/test-infra/pkg/prow"
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+const (
+	createAction = "create"
+	updateAction = "update"
+	closeAction  = "close"
+	reopenAction = "reopen"
+	renameAction = "rename"
+	linkAction   = "link"
+
+	// issueActionsFile is the reconciliation report in the artifacts
+	// directory
+	issueActionsFile = "issue_actions.txt"
+)
+
+// reIdentity captures the test, the repo and the job of the identity of an
+// issue, see getIdentityForTest. The job is missing from the identities of
+// issues shared by all jobs of a repo, see getRepoIdentityForTest.
+var reIdentity = regexp.MustCompile(`^'(.*)' in repo '(.*?)'(?: job '(.*)')?$`)
+
+// issueAction is an action taken on a Github issue, or that would be taken in
+// dry run mode
+type issueAction struct {
+	Action string
+	Test   string
+	Issue  string // URL of the issue, empty if it's created
+	Reason string
+}
+
+// recordAction adds an action to the reconciliation report
+func (gih *GithubIssueHandler) recordAction(action, test, issue, reason string) {
+	gih.actions = append(gih.actions, issueAction{Action: action, Test: test, Issue: issue, Reason: reason})
+}
+
+// writeIssueActions writes the reconciliation report, one action per line
+func writeIssueActions(w io.Writer, actions []issueAction) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tTEST\tISSUE\tREASON")
+	for _, a := range actions {
+		issue := a.Issue
+		if issue == "" {
+			issue = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", a.Action, a.Test, issue, a.Reason)
+	}
+	return tw.Flush()
+}
+
+// writeIssueActionsReport writes the reconciliation report in the artifacts
+// directory, and logs it in dry run mode as the actions were not taken
+func (gih *GithubIssueHandler) writeIssueActionsReport(dryrun bool) error {
+	var buf bytes.Buffer
+	if err := writeIssueActions(&buf, gih.actions); err != nil {
+		return err
+	}
+	if dryrun {
+		log.Printf("[dry run] issue actions that would be taken:\n%s", buf.String())
+	}
+	artifactsDir := prow.GetLocalArtifactsDir()
+	if err := helpers.CreateDir(artifactsDir); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(artifactsDir, issueActionsFile), buf.Bytes(), 0644)
+}
+
+// parseIdentity returns the test, the repo and the job of the identity of an
+// issue, job is empty for issues shared by all jobs of the repo, and ok is
+// false for bulk issues
+func parseIdentity(identity string) (test, repo, job string, ok bool) {
+	m := reIdentity.FindStringSubmatch(identity)
+	if len(m) != 4 {
+		return "", "", "", false
+	}
+	return m[1], m[2], m[3], true
+}
+
+// similarity returns the similarity of 2 strings between 0 and 1, based on
+// their Levenshtein distance
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(maxLen)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// setSeenTests records the tests that ran in each job
+func (gih *GithubIssueHandler) setSeenTests(repoDataAll []RepoData) {
+	gih.seenTests = make(map[string]map[string]sets.String)
+	for _, rd := range repoDataAll {
+		if _, ok := gih.seenTests[rd.Config.Repo]; !ok {
+			gih.seenTests[rd.Config.Repo] = make(map[string]sets.String)
+		}
+		if _, ok := gih.seenTests[rd.Config.Repo][rd.Config.Name]; !ok {
+			gih.seenTests[rd.Config.Repo][rd.Config.Name] = sets.NewString()
+		}
+		for testFullName := range rd.TestStats {
+			gih.seenTests[rd.Config.Repo][rd.Config.Name].Insert(testFullName)
+		}
+	}
+}
+
+// isTestSeen reports if the test ran in any scanned build of a job. An empty
+// job stands for any job of the repo, i.e. for issues shared by all jobs.
+func (gih *GithubIssueHandler) isTestSeen(repo, job, testFullName string) bool {
+	if job != "" {
+		return gih.seenTests[repo][job].Has(testFullName)
+	}
+	for _, tests := range gih.seenTests[repo] {
+		if tests.Has(testFullName) {
+			return true
+		}
+	}
+	return false
+}
+
+// findRenamedIssue finds the open issue of a test of the same job that no
+// longer runs, with the name most similar to testFullName above the rename
+// similarity of the job. It returns the identity of the issue, or an empty
+// string if there is none.
+func (gih *GithubIssueHandler) findRenamedIssue(rd RepoData, testFullName string, flakyIssuesMap map[string][]flakyIssue) string {
+	minSimilarity := rd.Config.Issues.WithDefaults().RenameSimilarity
+	var identities []string
+	for identity := range flakyIssuesMap {
+		identities = append(identities, identity)
+	}
+	sort.Strings(identities) // for a deterministic choice among equally similar tests
+
+	var best string
+	var bestSimilarity float64
+	for _, identity := range identities {
+		test, repo, job, ok := parseIdentity(identity)
+		if !ok || repo != rd.Config.Repo || (job != "" && job != rd.Config.Name) {
+			continue
+		}
+		if _, ok := rd.TestStats[test]; ok || gih.isTestSeen(repo, job, test) {
+			continue
+		}
+		fis := flakyIssuesMap[identity]
+		if len(fis) != 1 || fis[0].issue.GetState() != string(ghutil.IssueOpenState) {
+			continue
+		}
+		if _, issueRepo := getOrgRepoFromIssue(fis[0].issue); issueRepo != rd.Config.IssueRepo {
+			continue
+		}
+		if s := similarity(test, testFullName); s >= minSimilarity && s > bestSimilarity {
+			best, bestSimilarity = identity, s
+		}
+	}
+	return best
+}
+
+// renameIssue moves the issue of oldIdentity to the renamed test, see
+// moveIssue
+func (gih *GithubIssueHandler) renameIssue(rd RepoData, oldIdentity, testFullName string, flakyIssuesMap map[string][]flakyIssue, dryrun bool) error {
+	oldTest, _, _, _ := parseIdentity(oldIdentity)
+	return gih.moveIssue(rd, oldIdentity, testFullName,
+		fmt.Sprintf("renamed from '%s'", oldTest),
+		fmt.Sprintf("Test '%s' no longer runs, tracking its likely new name '%s' in this issue", oldTest, testFullName),
+		flakyIssuesMap, dryrun)
+}
+
+// moveRepoIssueToJob moves the issue of a test shared by all jobs of the repo
+// to the job of rd, see moveIssue. Other jobs create their own issue for the
+// test when it's flaky in them.
+func (gih *GithubIssueHandler) moveRepoIssueToJob(rd RepoData, testFullName string, flakyIssuesMap map[string][]flakyIssue, dryrun bool) error {
+	return gih.moveIssue(rd, getRepoIdentityForTest(testFullName, rd.Config.Repo), testFullName,
+		fmt.Sprintf("moved to job '%s'", rd.Config.Name),
+		fmt.Sprintf("Flaky tests are now tracked per job, tracking test '%s' of job '%s' in this issue", testFullName, rd.Config.Name),
+		flakyIssuesMap, dryrun)
+}
+
+// moveIssue moves the issue of oldIdentity to a test of the job of rd, both on
+// Github and in flakyIssuesMap, so that it's updated like the issue of the
+// test
+func (gih *GithubIssueHandler) moveIssue(rd RepoData, oldIdentity, testFullName, reason, comment string, flakyIssuesMap map[string][]flakyIssue, dryrun bool) error {
+	fi := flakyIssuesMap[oldIdentity][0]
+	identity := getIdentityForTest(testFullName, rd.Config.Repo, rd.Config.Name)
+	gih.recordAction(renameAction, testFullName, fi.issue.GetURL(), reason)
+	org, repo := getOrgRepoFromIssue(fi.issue)
+	if err := helpers.Run(
+		"renaming issue",
+		func() error {
+			body := fmt.Sprintf(issueBodyTemplate, testFullName, rd.Config.Repo, rd.Config.Name,
+				gih.createOwnersContent(rd, rd.TestStats[testFullName]), fmt.Sprintf(testIdentifierPattern, identity))
+			if err := gih.client.EditIssue(org, repo, fi.issue.GetNumber(), fmt.Sprintf("[flaky] %s", testFullName), body); err != nil {
+				return err
+			}
+			_, err := gih.client.CreateComment(org, repo, fi.issue.GetNumber(), comment)
+			return err
+		},
+		dryrun); err != nil {
+		return fmt.Errorf("failed renaming issue '%s': '%v'", fi.issue.GetURL(), err)
+	}
+	fi.identity = identity
+	delete(flakyIssuesMap, oldIdentity)
+	flakyIssuesMap[identity] = []flakyIssue{fi}
+	return nil
+}
+
+// createRelatedIssuesContent links the open issues of the same test in other
+// jobs, of the same repo or of other repos sharing the test
+func createRelatedIssuesContent(testFullName, identity string, flakyIssuesMap map[string][]flakyIssue) string {
+	var links []string
+	for otherIdentity, fis := range flakyIssuesMap {
+		if otherIdentity == identity {
+			continue
+		}
+		if test, _, _, ok := parseIdentity(otherIdentity); !ok || test != testFullName {
+			continue
+		}
+		for _, fi := range fis {
+			if fi.issue.GetState() == string(ghutil.IssueOpenState) {
+				links = append(links, fi.issue.GetHTMLURL())
+			}
+		}
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	sort.Strings(links)
+	return "\nAlso flaky in other jobs: " + strings.Join(links, ", ")
+}
+
+// closeStaleIssues closes the open issues of tests that didn't run in any
+// scanned build of their job for the stale days of the job, as the tests were
+// likely removed. Issues shared by all jobs of a repo use the longest stale
+// period of the jobs of the repo. The time a test last ran is approximated by
+// the last update of the auto comment, which is updated on every scan where
+// the test runs. Repos with unscannedJobs are skipped, as the tests of these
+// jobs may still run.
+func (gih *GithubIssueHandler) closeStaleIssues(repoDataAll []RepoData, unscannedJobs []config.JobConfig, flakyIssuesMap map[string][]flakyIssue, now time.Time, dryrun bool) ([]string, error) {
+	unscannedRepos := sets.NewString()
+	for _, jc := range unscannedJobs {
+		unscannedRepos.Insert(jc.Repo)
+	}
+
+	// Stale days of each job, key "" is the longest stale period of the jobs
+	// of the repo
+	staleDays := make(map[string]map[string]int)
+	for _, rd := range repoDataAll {
+		if rd.Config.IssueRepo == "" {
+			continue
+		}
+		if _, ok := staleDays[rd.Config.Repo]; !ok {
+			staleDays[rd.Config.Repo] = make(map[string]int)
+		}
+		days := rd.Config.Issues.WithDefaults().StaleDays
+		staleDays[rd.Config.Repo][rd.Config.Name] = days
+		if days > staleDays[rd.Config.Repo][""] {
+			staleDays[rd.Config.Repo][""] = days
+		}
+	}
+
+	var identities []string
+	for identity := range flakyIssuesMap {
+		identities = append(identities, identity)
+	}
+	sort.Strings(identities)
+
+	var (
+		messages []string
+		errs     []error
+	)
+	for _, identity := range identities {
+		test, repo, job, ok := parseIdentity(identity)
+		if !ok || unscannedRepos.Has(repo) {
+			continue
+		}
+		// Tests of jobs not scanned, or that ran, are not stale
+		days, ok := staleDays[repo][job]
+		if !ok || gih.isTestSeen(repo, job, test) {
+			continue
+		}
+		for _, fi := range flakyIssuesMap[identity] {
+			if fi.issue.GetState() != string(ghutil.IssueOpenState) {
+				continue
+			}
+			lastSeen := fi.comment.GetUpdatedAt()
+			if lastSeen.IsZero() {
+				lastSeen = fi.issue.GetCreatedAt()
+			}
+			if now.Sub(lastSeen) < time.Duration(days)*24*time.Hour {
+				continue
+			}
+			reason := fmt.Sprintf("not run in any build for %d days", days)
+			gih.recordAction(closeAction, test, fi.issue.GetURL(), reason)
+			message := fmt.Sprintf("Closing stale issue '%s' for '%s'", fi.issue.GetURL(), identity)
+			log.Println(message)
+			messages = append(messages, message)
+			org, issueRepo := getOrgRepoFromIssue(fi.issue)
+			if err := helpers.Run(
+				"closing stale issue",
+				func() error {
+					if err := gih.client.CloseIssue(org, issueRepo, fi.issue.GetNumber()); err != nil {
+						return err
+					}
+					_, err := gih.client.CreateComment(org, issueRepo, fi.issue.GetNumber(),
+						fmt.Sprintf("Closing issue: this test was %s, it was likely removed", reason))
+					return err
+				},
+				dryrun); err != nil {
+				errs = append(errs, fmt.Errorf("failed closing stale issue '%s': '%v'", fi.issue.GetURL(), err))
+			}
+		}
+	}
+	return messages, helpers.CombineErrors(errs)
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"knative.dev/test-infra/pkg/ghutil/fakeghutil"
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"abc", "abc", 1},
+		{"abc", "", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"e2e.TestAutoscale", "e2e.TestAutoscaling", 1 - 3.0/19},
+	}
+	for _, test := range tests {
+		if got := similarity(test.a, test.b); got != test.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestParseIdentity(t *testing.T) {
+	test, repo, job, ok := parseIdentity(getIdentityForTest("e2e.TestA", "serving", "continuous"))
+	if !ok || test != "e2e.TestA" || repo != "serving" || job != "continuous" {
+		t.Errorf("parseIdentity() = %q, %q, %q, %v, want %q, %q, %q, true", test, repo, job, ok, "e2e.TestA", "serving", "continuous")
+	}
+	test, repo, job, ok = parseIdentity(getRepoIdentityForTest("e2e.TestA", "serving"))
+	if !ok || test != "e2e.TestA" || repo != "serving" || job != "" {
+		t.Errorf("parseIdentity() = %q, %q, %q, %v, want %q, %q, %q, true", test, repo, job, ok, "e2e.TestA", "serving", "")
+	}
+	if _, _, _, ok := parseIdentity("10.00% tests failed in repo serving on now"); ok {
+		t.Error("parseIdentity() of a bulk issue = true, want false")
+	}
+}
+
+// renameTest moves the results of a test of rd to a new name
+func renameTest(rd RepoData, oldName, newName string) RepoData {
+	ts := *rd.TestStats[oldName]
+	ts.TestName = newName
+	tss := map[string]*TestStat{newName: &ts}
+	for name, other := range rd.TestStats {
+		if name != oldName {
+			tss[name] = other
+		}
+	}
+	rd.TestStats = tss
+	return rd
+}
+
+func TestRenamedTest(t *testing.T) {
+	tests := []struct {
+		name       string
+		newName    string
+		wantIssues int
+	}{
+		{"similar name", "testflaky_00", 1},
+		{"different name", "TestSomethingElse", 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fgih := getFakeGithubIssueHandler()
+			rd := createRepoData(200, 1, 0, 0, fakeRepo, int64(0))
+			fgih.processGithubIssuesForRepo(rd, make(map[string][]flakyIssue), dryrun)
+			flakyIssuesMap, err := fgih.getFlakyIssues([]RepoData{rd})
+			if err != nil {
+				t.Fatalf("getFlakyIssues() = %v", err)
+			}
+
+			renamed := renameTest(rd, "testflaky_0", test.newName)
+			*renamed.LastBuildStartTime++
+			fgih.setSeenTests([]RepoData{renamed})
+			if _, _, err := fgih.processGithubIssuesForRepo(renamed, flakyIssuesMap, dryrun); err != nil {
+				t.Fatalf("processGithubIssuesForRepo() = %v", err)
+			}
+			issues, _ := fgih.client.ListIssuesByRepo(fakeOrg, fakeRepo, []string{})
+			if len(issues) != test.wantIssues {
+				t.Fatalf("got %d issues, want %d", len(issues), test.wantIssues)
+			}
+			for _, issue := range issues {
+				if issue.GetTitle() != "[flaky] "+test.newName {
+					continue
+				}
+				if !strings.Contains(issue.GetBody(), getIdentityForTest(test.newName, fakeRepo, fakeJob)) {
+					t.Errorf("issue body = %q, want the identity of %q", issue.GetBody(), test.newName)
+				}
+				if _, ok := flakyIssuesMap[getIdentityForTest(test.newName, fakeRepo, fakeJob)]; !ok && test.wantIssues == 1 {
+					t.Errorf("flakyIssuesMap doesn't contain the renamed issue")
+				}
+				return
+			}
+			t.Errorf("no issue for %q", test.newName)
+		})
+	}
+}
+
+func TestIssuesPerJob(t *testing.T) {
+	fgih := getFakeGithubIssueHandler()
+	continuous := createRepoData(200, 1, 0, 0, fakeRepo, int64(0))
+	nightly := createRepoData(200, 1, 0, 0, fakeRepo, int64(0))
+	nightly.Config.Name = "nightly"
+
+	// An issue created before issues were per job is shared by all jobs
+	fgih.processGithubIssuesForRepo(continuous, make(map[string][]flakyIssue), dryrun)
+	issues, _ := fgih.client.ListIssuesByRepo(fakeOrg, fakeRepo, []string{})
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1", len(issues))
+	}
+	shared := issues[0]
+	body := strings.Replace(shared.GetBody(), getIdentityForTest("testflaky_0", fakeRepo, fakeJob), getRepoIdentityForTest("testflaky_0", fakeRepo), 1)
+	if err := fgih.client.EditIssue(fakeOrg, fakeRepo, shared.GetNumber(), shared.GetTitle(), body); err != nil {
+		t.Fatalf("EditIssue() = %v", err)
+	}
+	flakyIssuesMap, err := fgih.getFlakyIssues([]RepoData{continuous})
+	if err != nil {
+		t.Fatalf("getFlakyIssues() = %v", err)
+	}
+
+	// The first job running the test takes the issue over, the other job
+	// creates its own issue linking it
+	*continuous.LastBuildStartTime++
+	*nightly.LastBuildStartTime++
+	fgih.setSeenTests([]RepoData{continuous, nightly})
+	for _, rd := range []RepoData{continuous, nightly} {
+		if _, _, err := fgih.processGithubIssuesForRepo(rd, flakyIssuesMap, dryrun); err != nil {
+			t.Fatalf("processGithubIssuesForRepo() = %v", err)
+		}
+	}
+	if !strings.Contains(shared.GetBody(), getIdentityForTest("testflaky_0", fakeRepo, fakeJob)) {
+		t.Errorf("shared issue body = %q, want the identity of job %q", shared.GetBody(), fakeJob)
+	}
+	if _, ok := flakyIssuesMap[getRepoIdentityForTest("testflaky_0", fakeRepo)]; ok {
+		t.Error("flakyIssuesMap still contains the shared issue")
+	}
+	issues, _ = fgih.client.ListIssuesByRepo(fakeOrg, fakeRepo, []string{})
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	for _, issue := range issues {
+		if issue.GetNumber() == shared.GetNumber() {
+			continue
+		}
+		if !strings.Contains(issue.GetBody(), getIdentityForTest("testflaky_0", fakeRepo, "nightly")) {
+			t.Errorf("issue body = %q, want the identity of job %q", issue.GetBody(), "nightly")
+		}
+		comments, _ := fgih.client.ListComments(fakeOrg, fakeRepo, issue.GetNumber())
+		if len(comments) != 1 || !strings.Contains(comments[0].GetBody(), "Also flaky in other jobs: "+shared.GetHTMLURL()) {
+			t.Errorf("issue comments = %v, want a link to %s", comments, shared.GetHTMLURL())
+		}
+	}
+}
+
+func TestCloseStaleIssues(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		lastUpdate time.Time
+		testRuns   bool
+		unscanned  []config.JobConfig
+		wantState  string
+	}{
+		{"stale", now.AddDate(0, 0, -31), false, nil, "closed"},
+		{"recently updated", now.AddDate(0, 0, -1), false, nil, "open"},
+		{"test still runs", now.AddDate(0, 0, -31), true, nil, "open"},
+		{"other job of the repo not scanned", now.AddDate(0, 0, -31), false, []config.JobConfig{{Name: "nightly", Repo: fakeRepo}}, "open"},
+		{"job of another repo not scanned", now.AddDate(0, 0, -31), false, []config.JobConfig{{Name: "nightly", Repo: "other"}}, "closed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fgih := getFakeGithubIssueHandler()
+			rd := createRepoData(200, 1, 0, 0, fakeRepo, int64(0))
+			fgih.processGithubIssuesForRepo(rd, make(map[string][]flakyIssue), dryrun)
+			flakyIssuesMap, err := fgih.getFlakyIssues([]RepoData{rd})
+			if err != nil {
+				t.Fatalf("getFlakyIssues() = %v", err)
+			}
+			fi := flakyIssuesMap[getIdentityForTest("testflaky_0", fakeRepo, fakeJob)][0]
+			fi.comment.UpdatedAt = &test.lastUpdate
+
+			if !test.testRuns {
+				delete(rd.TestStats, "testflaky_0")
+			}
+			fgih.setSeenTests([]RepoData{rd})
+			if _, err := fgih.closeStaleIssues([]RepoData{rd}, test.unscanned, flakyIssuesMap, now, dryrun); err != nil {
+				t.Fatalf("closeStaleIssues() = %v", err)
+			}
+			if got := fi.issue.GetState(); got != test.wantState {
+				t.Errorf("issue state = %q, want %q", got, test.wantState)
+			}
+		})
+	}
+}
+
+func TestFailingTestUpdatesIssue(t *testing.T) {
+	fgih := getFakeGithubIssueHandler()
+	rd := createRepoData(200, 1, 0, 0, fakeRepo, int64(0))
+	fgih.processGithubIssuesForRepo(rd, make(map[string][]flakyIssue), dryrun)
+	flakyIssuesMap, err := fgih.getFlakyIssues([]RepoData{rd})
+	if err != nil {
+		t.Fatalf("getFlakyIssues() = %v", err)
+	}
+	fi := flakyIssuesMap[getIdentityForTest("testflaky_0", fakeRepo, fakeJob)][0]
+	lastUpdate := time.Now().AddDate(0, 0, -31)
+	fi.comment.UpdatedAt = &lastUpdate
+
+	// The test fails in all runs now, the auto comment still tells it ran
+	failed := testStatsMapForTest["failed"]
+	rd.TestStats["testflaky_0"] = &failed
+	*rd.LastBuildStartTime++
+	if _, _, err := fgih.processGithubIssuesForRepo(rd, flakyIssuesMap, dryrun); err != nil {
+		t.Fatalf("processGithubIssuesForRepo() = %v", err)
+	}
+	if !fi.comment.GetUpdatedAt().After(lastUpdate) {
+		t.Errorf("auto comment updated at %v, want it updated", fi.comment.GetUpdatedAt())
+	}
+	if fi.issue.GetState() != "open" {
+		t.Errorf("issue state = %q, want open", fi.issue.GetState())
+	}
+}
+
+// failingCloseClient fails closing issues
+type failingCloseClient struct {
+	*fakeghutil.FakeGithubClient
+}
+
+func (c failingCloseClient) CloseIssue(org, repo string, issueNumber int) error {
+	return errors.New("closing failed")
+}
+
+func TestProcessGithubIssues_CloseStaleIssuesError(t *testing.T) {
+	t.Setenv("ARTIFACTS", t.TempDir())
+	fgih := getFakeGithubIssueHandler()
+	rd := createRepoData(200, 1, 0, 0, fakeRepo, int64(0))
+	fgih.processGithubIssuesForRepo(rd, make(map[string][]flakyIssue), dryrun)
+
+	// The test doesn't run anymore, and its issue was never updated
+	delete(rd.TestStats, "testflaky_0")
+	fgih.client = failingCloseClient{fgih.client.(*fakeghutil.FakeGithubClient)}
+	if _, err := fgih.processGithubIssues([]RepoData{rd}, nil, dryrun); err == nil || !strings.Contains(err.Error(), "closing failed") {
+		t.Errorf("processGithubIssues() = %v, want the error closing the stale issue", err)
+	}
+}
+
+func TestCreateRelatedIssuesContent(t *testing.T) {
+	newIssue := func(state, url string) flakyIssue {
+		return flakyIssue{issue: &github.Issue{State: &state, HTMLURL: &url}}
+	}
+	flakyIssuesMap := map[string][]flakyIssue{
+		getIdentityForTest("e2e.TestA", "serving", "continuous"):  {newIssue("open", "https://github.com/knative/serving/issues/1")},
+		getIdentityForTest("e2e.TestA", "serving", "nightly"):     {newIssue("open", "https://github.com/knative/serving/issues/5")},
+		getIdentityForTest("e2e.TestA", "eventing", "continuous"): {newIssue("open", "https://github.com/knative/eventing/issues/2")},
+		getIdentityForTest("e2e.TestA", "client", "continuous"):   {newIssue("closed", "https://github.com/knative/client/issues/3")},
+		getIdentityForTest("e2e.TestB", "client", "continuous"):   {newIssue("open", "https://github.com/knative/client/issues/4")},
+	}
+	got := createRelatedIssuesContent("e2e.TestA", getIdentityForTest("e2e.TestA", "serving", "continuous"), flakyIssuesMap)
+	if want := "\nAlso flaky in other jobs: https://github.com/knative/eventing/issues/2, https://github.com/knative/serving/issues/5"; got != want {
+		t.Errorf("createRelatedIssuesContent() = %q, want %q", got, want)
+	}
+	if got := createRelatedIssuesContent("e2e.TestB", getIdentityForTest("e2e.TestB", "client", "continuous"), flakyIssuesMap); got != "" {
+		t.Errorf("createRelatedIssuesContent() = %q, want empty", got)
+	}
+}
+
+func TestWriteIssueActions(t *testing.T) {
+	fgih := getFakeGithubIssueHandler()
+	fgih.recordAction(createAction, "e2e.TestA", "", "flaky")
+	fgih.recordAction(closeAction, "e2e.TestB", "https://github.com/knative/serving/issues/1", "not run in any build for 30 days")
+	var buf bytes.Buffer
+	if err := writeIssueActions(&buf, fgih.actions); err != nil {
+		t.Fatalf("writeIssueActions() = %v", err)
+	}
+	want := `ACTION  TEST       ISSUE                                        REASON
+create  e2e.TestA  -                                            flaky
+close   e2e.TestB  https://github.com/knative/serving/issues/1  not run in any build for 30 days
+`
+	if got := buf.String(); got != want {
+		t.Errorf("writeIssueActions() = %q, want %q", got, want)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"knative.dev/test-infra/pkg/prow"
 	"knative.dev/test-infra/pkg/slackutil"
 	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+	"knative.dev/test-infra/tools/flaky-test-reporter/history"
 )
 
 var (
@@ -42,11 +43,21 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == queryCommand {
+		if err := runQuery(os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Failed querying history: %v", err)
+		}
+		return
+	}
+
 	githubAccount := flag.String("github-account", "", "Token file for Github authentication")
 	slackAccount := flag.String("slack-account", "", "slack secret file for authenticating with Slack")
 	buildsCountOverride := flag.Int("build-count", 5, "count of builds to scan")
 	skipReport := flag.Bool("skip-report", false, "skip Github and Slack report")
 	dryrun := flag.Bool("dry-run", false, "dry run switch")
+	quarantine := flag.Bool("quarantine", false, "update the skip lists of flaky tests configured for jobs, through pull requests")
+	slackDigest := flag.Bool("slack-digest", false, "send the weekly digest of flaky tests to Slack instead of the flaky tests of this run, requires a history store")
+	hf := addHistoryFlags(flag.CommandLine)
 	flag.Parse()
 
 	buildsCount = *buildsCountOverride
@@ -56,28 +67,50 @@ func main() {
 		log.Printf("running in [dry run mode]")
 	}
 
-	if err := prow.Initialize(); err != nil {
-		log.Fatalf("Failed authenticating GCS: '%v'", err)
+	historyStore, err := hf.newStore()
+	if err != nil {
+		log.Fatalf("Failed creating history store: %v", err)
+	}
+	if *slackDigest && historyStore == nil {
+		log.Fatalf("--slack-digest requires --history-file or --history-db-host")
+	}
+
+	if usesProwSource(config.JobConfigs) {
+		if err := prow.Initialize(); err != nil {
+			log.Fatalf("Failed authenticating GCS: '%v'", err)
+		}
 	}
 
 	var repoDataAll []RepoData
 	// Clean up local artifacts directory, this will be used later for artifacts uploads
-	err := os.RemoveAll(prow.GetLocalArtifactsDir()) // this function returns nil if path not found
+	err = os.RemoveAll(prow.GetLocalArtifactsDir()) // this function returns nil if path not found
 	if err != nil {
 		log.Fatalf("Failed removing local artifacts directory: %v", err)
 	}
 	var jobErrs []error
+	// Jobs whose results are missing, their tests are not known to be removed
+	var unscannedJobs []config.JobConfig
 	for _, jc := range config.JobConfigs {
 		log.Printf("collecting results for job '%s' in repo '%s'\n", jc.Name, jc.Repo)
-		rd, err := collectTestResultsForRepo(jc)
+		src, err := newResultSource(jc, *githubAccount)
+		if err != nil {
+			err = fmt.Errorf("WARNING: error creating result source for job '%s' in repo '%s': %v", jc.Name, jc.Repo, err)
+			log.Printf("%v", err)
+			jobErrs = append(jobErrs, err)
+			unscannedJobs = append(unscannedJobs, jc)
+			continue
+		}
+		rd, err := collectTestResultsForRepo(jc, src)
 		if err != nil {
 			err = fmt.Errorf("WARNING: error collecting results for job '%s' in repo '%s': %v", jc.Name, jc.Repo, err)
 			log.Printf("%v", err)
 			jobErrs = append(jobErrs, err)
+			unscannedJobs = append(unscannedJobs, jc)
 			continue
 		}
 		if rd.LastBuildStartTime == nil {
 			log.Printf("WARNING: no build found, skipping '%s' in repo '%s'", jc.Name, jc.Repo)
+			unscannedJobs = append(unscannedJobs, jc)
 			continue
 		}
 		if err = createArtifactForRepo(*rd); err != nil {
@@ -91,15 +124,30 @@ func main() {
 	// happens, it should fail the job after Slack notification
 	jobErr := helpers.CombineErrors(jobErrs)
 	jsonErr := writeFlakyTestsToJSON(repoDataAll, *dryrun)
+	var historyErr error
+	if historyStore != nil {
+		historyErr = recordHistory(historyStore, repoDataAll, *dryrun)
+	}
 
-	var ghErr, slackErr error
+	var ghErr, slackErr, quarantineErr error
 	var flakyIssues map[string][]flakyIssue
 
 	if *skipReport {
 		log.Printf("--skip-report provided, skipping Github and Slack report")
 	} else {
-		flakyIssues, ghErr = githubOperations(*githubAccount, repoDataAll, *dryrun)
-		slackErr = slackOperations(*slackAccount, repoDataAll, flakyIssues, *dryrun)
+		flakyIssues, ghErr = githubOperations(*githubAccount, repoDataAll, unscannedJobs, *dryrun)
+		if *slackDigest {
+			slackErr = slackDigestOperations(*slackAccount, repoDataAll, flakyIssues, historyStore, *dryrun)
+		} else {
+			slackErr = slackOperations(*slackAccount, repoDataAll, flakyIssues, *dryrun)
+		}
+		if *quarantine {
+			quarantineErr = quarantineOperations(*githubAccount, repoDataAll, flakyIssues, *dryrun)
+		}
+	}
+
+	if historyStore != nil {
+		historyStore.Close()
 	}
 
 	if jobErr != nil {
@@ -111,19 +159,45 @@ func main() {
 	if jsonErr != nil {
 		log.Printf("JSON step failures:\n%v", jsonErr)
 	}
+	if historyErr != nil {
+		log.Printf("History step failures:\n%v", historyErr)
+	}
+	if quarantineErr != nil {
+		log.Printf("Quarantine step failures:\n%v", quarantineErr)
+	}
 	// Fail this job if there is any error
-	if jobErr != nil || jsonErr != nil || ghErr != nil || slackErr != nil {
+	if jobErr != nil || jsonErr != nil || historyErr != nil || ghErr != nil || slackErr != nil || quarantineErr != nil {
 		os.Exit(1)
 	}
 }
 
-func githubOperations(ghToken string, repoData []RepoData, dryrun bool) (map[string][]flakyIssue, error) {
+// usesProwSource reports if any job reads its results from Prow in GCS,
+// which needs GCS authentication
+func usesProwSource(jcs []config.JobConfig) bool {
+	for _, jc := range jcs {
+		if (jc.Source == "" || jc.Source == config.ProwSource) && jc.Path == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func githubOperations(ghToken string, repoData []RepoData, unscannedJobs []config.JobConfig, dryrun bool) (map[string][]flakyIssue, error) {
 	gih, err := Setup(ghToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return gih.processGithubIssues(repoData, dryrun)
+	return gih.processGithubIssues(repoData, unscannedJobs, dryrun)
+}
+
+func quarantineOperations(ghToken string, repoData []RepoData, flakyIssues map[string][]flakyIssue, dryrun bool) error {
+	gih, err := Setup(ghToken)
+	if err != nil {
+		return err
+	}
+
+	return gih.processQuarantine(repoData, flakyIssues, dryrun)
 }
 
 func isWeekend(t time.Time) bool {
@@ -137,8 +211,8 @@ func slackOperations(slackToken string, repoData []RepoData, flakyIssues map[str
 		return nil
 	}
 
-	// Verify that there are issues to notify on.
-	if len(flakyIssues) == 0 {
+	// Verify that there are issues or infrastructure failures to notify on.
+	if len(flakyIssues) == 0 && !hasOutageBuilds(repoData) {
 		return nil
 	}
 
@@ -147,5 +221,21 @@ func slackOperations(slackToken string, repoData []RepoData, flakyIssues map[str
 		return err
 	}
 
-	return sendSlackNotifications(repoData, client, flakyIssues, dryrun)
+	return sendSlackNotifications(repoData, client, func(rd RepoData) (string, error) {
+		return createSlackMessageForRepo(rd, flakyIssues), nil
+	}, dryrun)
+}
+
+// slackDigestOperations sends the weekly digest of flaky tests, computed from
+// the history store, in place of the flaky tests of this run
+func slackDigestOperations(slackToken string, repoData []RepoData, flakyIssues map[string][]flakyIssue, store history.Store, dryrun bool) error {
+	client, err := slackutil.NewWriteClient(knativeBotName, slackToken)
+	if err != nil && !dryrun { // Dryrun doesn't do any Slack operation
+		return err
+	}
+
+	now := time.Now()
+	return sendSlackNotifications(repoData, client, func(rd RepoData) (string, error) {
+		return createDigestMessageForRepo(store, rd, flakyIssues, now)
+	}, dryrun)
 }
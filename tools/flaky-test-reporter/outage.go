@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// outage.go detects builds where most tests failed together because of an
+// infrastructure failure, and excludes them from the results of tests
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+// OutageBuild is a build where a large fraction of tests failed together,
+// most likely because of the infrastructure (i.e. cluster creation or a
+// registry outage) rather than because of the tests
+type OutageBuild struct {
+	BuildID int
+	Failed  int // count of tests failed in the build
+	Ran     int // count of tests passed or failed in the build
+}
+
+// getOutageBuilds returns the builds where the ratio of failed tests is above
+// the outage ratio, in the order of BuildIDs
+func (rd *RepoData) getOutageBuilds(fc config.FlakinessConfig) []OutageBuild {
+	ran, failed := rd.countResultsPerBuild()
+	var outages []OutageBuild
+	for _, buildID := range rd.BuildIDs {
+		count := failed[buildID]
+		if count >= fc.OutageMinFailures && float64(count)/float64(ran[buildID]) > fc.OutageRatio {
+			outages = append(outages, OutageBuild{BuildID: buildID, Failed: count, Ran: ran[buildID]})
+		}
+	}
+	return outages
+}
+
+// excludeOutageBuilds moves outage builds from BuildIDs to OutageBuilds, and
+// removes them from the results of all tests, so that tests are not reported
+// as flaky because of the infrastructure
+func (rd *RepoData) excludeOutageBuilds() {
+	outages := rd.getOutageBuilds(rd.Config.Flakiness.WithDefaults())
+	if len(outages) == 0 {
+		return
+	}
+	excluded := sets.NewInt()
+	for _, ob := range outages {
+		log.Printf("build %d of job '%s' is an infrastructure failure, %d out of %d tests failed",
+			ob.BuildID, rd.Config.Name, ob.Failed, ob.Ran)
+		excluded.Insert(ob.BuildID)
+	}
+	rd.OutageBuilds = outages
+	rd.BuildIDs = withoutBuilds(rd.BuildIDs, excluded)
+	for _, ts := range rd.TestStats {
+		ts.Passed = withoutBuilds(ts.Passed, excluded)
+		ts.Skipped = withoutBuilds(ts.Skipped, excluded)
+		ts.Failed = withoutBuilds(ts.Failed, excluded)
+		ts.FlakyInBuild = withoutBuilds(ts.FlakyInBuild, excluded)
+		for buildID := range excluded {
+			delete(ts.FailureSignatures, buildID)
+		}
+	}
+}
+
+// withoutBuilds returns buildIDs without the excluded ones
+func withoutBuilds(buildIDs []int, excluded sets.Int) []int {
+	var res []int
+	for _, buildID := range buildIDs {
+		if !excluded.Has(buildID) {
+			res = append(res, buildID)
+		}
+	}
+	return res
+}
+
+// createOutageMessage lists the outage builds of RepoData for the Slack
+// message, or returns an empty string if there is none
+func createOutageMessage(rd RepoData) string {
+	if len(rd.OutageBuilds) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%d builds were excluded as infrastructure failures, as most tests failed together:", len(rd.OutageBuilds))
+	for _, ob := range rd.OutageBuilds {
+		message += fmt.Sprintf("\n>- %d out of %d tests failed in %s", ob.Failed, ob.Ran, rd.getBuildURL(ob.BuildID))
+	}
+	return message
+}
+
+// hasOutageBuilds reports if any RepoData has outage builds
+func hasOutageBuilds(repoDataAll []RepoData) bool {
+	for _, rd := range repoDataAll {
+		if len(rd.OutageBuilds) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newOutageRepoData returns RepoData of 10 tests over builds 3, 2 and 1, where
+// failedIn[buildID] tests failed in a build and the others passed
+func newOutageRepoData(failedIn map[int]int) *RepoData {
+	rd := &RepoData{
+		BuildIDs:  []int{3, 2, 1},
+		BuildURLs: map[int]string{2: "https://ci/2"},
+		TestStats: make(map[string]*TestStat),
+	}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("suite.test%d", i)
+		ts := &TestStat{TestName: name}
+		for _, buildID := range rd.BuildIDs {
+			if i < failedIn[buildID] {
+				ts.Failed = append(ts.Failed, buildID)
+			} else {
+				ts.Passed = append(ts.Passed, buildID)
+			}
+		}
+		rd.TestStats[name] = ts
+	}
+	return rd
+}
+
+func TestExcludeOutageBuilds(t *testing.T) {
+	tests := []struct {
+		name        string
+		failedIn    map[int]int
+		wantOutages []OutageBuild
+		wantBuilds  []int
+	}{{
+		name:       "no failure",
+		wantBuilds: []int{3, 2, 1},
+	}, {
+		name:       "few failures",
+		failedIn:   map[int]int{2: 2, 1: 5},
+		wantBuilds: []int{3, 2, 1},
+	}, {
+		name:        "most tests failed",
+		failedIn:    map[int]int{2: 8, 1: 1},
+		wantOutages: []OutageBuild{{BuildID: 2, Failed: 8, Ran: 10}},
+		wantBuilds:  []int{3, 1},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := newOutageRepoData(tt.failedIn)
+			rd.excludeOutageBuilds()
+			if !reflect.DeepEqual(rd.OutageBuilds, tt.wantOutages) {
+				t.Errorf("OutageBuilds = %v, want %v", rd.OutageBuilds, tt.wantOutages)
+			}
+			if !reflect.DeepEqual(rd.BuildIDs, tt.wantBuilds) {
+				t.Errorf("BuildIDs = %v, want %v", rd.BuildIDs, tt.wantBuilds)
+			}
+			for name, ts := range rd.TestStats {
+				for _, ob := range tt.wantOutages {
+					if intSliceContains(ts.Passed, ob.BuildID) || intSliceContains(ts.Failed, ob.BuildID) {
+						t.Errorf("TestStats[%q] = %v, still has results of build %d", name, ts, ob.BuildID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateOutageMessage(t *testing.T) {
+	rd := newOutageRepoData(map[int]int{2: 8})
+	if got := createOutageMessage(*rd); got != "" {
+		t.Errorf("createOutageMessage() before exclusion = %q, want empty", got)
+	}
+	rd.excludeOutageBuilds()
+	got := createOutageMessage(*rd)
+	if !strings.Contains(got, "8 out of 10 tests failed in https://ci/2") {
+		t.Errorf("createOutageMessage() = %q, want it to list build 2", got)
+	}
+	if !hasOutageBuilds([]RepoData{{}, *rd}) {
+		t.Error("hasOutageBuilds() = false, want true")
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// owners.go finds the approvers owning tests from the OWNERS files of repos
+
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	ownersFileName        = "OWNERS"
+	ownersAliasesFileName = "OWNERS_ALIASES"
+)
+
+// ownersFile is the subset of an OWNERS file used for routing
+type ownersFile struct {
+	Approvers []string `json:"approvers"`
+}
+
+// ownersAliases is an OWNERS_ALIASES file, aliases are groups of users that
+// can be used in OWNERS files
+type ownersAliases struct {
+	Aliases map[string][]string `json:"aliases"`
+}
+
+// fileGetter reads files of repos, see ghutil.GithubOperations
+type fileGetter interface {
+	GetFile(org, repo, path, ref string) ([]byte, string, error)
+}
+
+// ownersResolver finds the approvers of tests, caching the OWNERS files read
+type ownersResolver struct {
+	client  fileGetter
+	files   map[string]*ownersFile         // key is org/repo/path, nil if missing
+	aliases map[string]map[string][]string // key is org/repo
+}
+
+func newOwnersResolver(client fileGetter) *ownersResolver {
+	return &ownersResolver{
+		client:  client,
+		files:   make(map[string]*ownersFile),
+		aliases: make(map[string]map[string][]string),
+	}
+}
+
+// suiteDir guesses the directory of a test suite in repo from its name, which
+// is usually its Go package, i.e. "knative.dev/serving/test/e2e" is
+// "test/e2e" in repo "serving". It's the root of the repo if the repo is not
+// part of the suite name.
+func suiteDir(suite, repo string) string {
+	parts := strings.Split(suite, "/")
+	for i, part := range parts {
+		if part == repo {
+			return path.Join(parts[i+1:]...)
+		}
+	}
+	return ""
+}
+
+// getOwnersFile reads and caches the OWNERS file at filePath, or returns nil
+// if it doesn't exist
+func (or *ownersResolver) getOwnersFile(org, repo, filePath string) (*ownersFile, error) {
+	key := path.Join(org, repo, filePath)
+	if of, ok := or.files[key]; ok {
+		return of, nil
+	}
+	contents, _, err := or.client.GetFile(org, repo, filePath, "")
+	if err != nil {
+		return nil, err
+	}
+	var of *ownersFile
+	if contents != nil {
+		of = &ownersFile{}
+		if err := yaml.Unmarshal(contents, of); err != nil {
+			return nil, fmt.Errorf("failed parsing '%s' of '%s/%s': %v", filePath, org, repo, err)
+		}
+	}
+	or.files[key] = of
+	return of, nil
+}
+
+// getAliases reads and caches the OWNERS_ALIASES file of repo
+func (or *ownersResolver) getAliases(org, repo string) (map[string][]string, error) {
+	key := path.Join(org, repo)
+	if aliases, ok := or.aliases[key]; ok {
+		return aliases, nil
+	}
+	contents, _, err := or.client.GetFile(org, repo, ownersAliasesFileName, "")
+	if err != nil {
+		return nil, err
+	}
+	oa := &ownersAliases{}
+	if err := yaml.Unmarshal(contents, oa); err != nil {
+		return nil, fmt.Errorf("failed parsing '%s' of '%s/%s': %v", ownersAliasesFileName, org, repo, err)
+	}
+	or.aliases[key] = oa.Aliases
+	return oa.Aliases, nil
+}
+
+// getApprovers returns the approvers of the closest OWNERS file with
+// approvers, from the directory of the test up to the root of repo. Aliases
+// are expanded.
+func (or *ownersResolver) getApprovers(org, repo string, ts *TestStat) ([]string, error) {
+	dir := suiteDir(ts.Suite, repo)
+	for {
+		of, err := or.getOwnersFile(org, repo, path.Join(dir, ownersFileName))
+		if err != nil {
+			return nil, err
+		}
+		if of != nil && len(of.Approvers) > 0 {
+			aliases, err := or.getAliases(org, repo)
+			if err != nil {
+				return nil, err
+			}
+			approvers := sets.NewString()
+			for _, approver := range of.Approvers {
+				if users, ok := aliases[approver]; ok {
+					approvers.Insert(users...)
+				} else {
+					approvers.Insert(approver)
+				}
+			}
+			return approvers.List(), nil
+		}
+		if dir == "" {
+			return nil, nil
+		}
+		if dir = path.Dir(dir); dir == "." {
+			dir = ""
+		}
+	}
+}
+
+// mentionUsers creates the Github mentions of users
+func mentionUsers(users []string) string {
+	mentions := make([]string, len(users))
+	for i, user := range users {
+		mentions[i] = "@" + user
+	}
+	return strings.Join(mentions, " ")
+}
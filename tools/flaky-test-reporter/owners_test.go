@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"knative.dev/test-infra/pkg/ghutil/fakeghutil"
+)
+
+func TestSuiteDir(t *testing.T) {
+	tests := []struct {
+		suite string
+		want  string
+	}{
+		{"knative.dev/serving/test/e2e", "test/e2e"},
+		{"knative.dev/serving", ""},
+		{"github.com/knative/serving/pkg/reconciler", "pkg/reconciler"},
+		{"e2e", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := suiteDir(test.suite, "serving"); got != test.want {
+			t.Errorf("suiteDir(%q) = %q, want %q", test.suite, got, test.want)
+		}
+	}
+}
+
+func TestGetApprovers(t *testing.T) {
+	fg := fakeghutil.NewFakeGithubClient()
+	fg.FileContents[fakeRepo] = map[string]map[string][]byte{
+		fakeghutil.DefaultBranch: {
+			"OWNERS":          []byte("approvers:\n- root-approvers\n"),
+			"OWNERS_ALIASES":  []byte("aliases:\n  root-approvers:\n  - alice\n  - bob\n"),
+			"test/OWNERS":     []byte("reviewers:\n- carol\n"),
+			"test/e2e/OWNERS": []byte("approvers:\n- dave\n- root-approvers\n"),
+		},
+	}
+	or := newOwnersResolver(fg)
+
+	tests := []struct {
+		suite string
+		want  []string
+	}{
+		{"knative.dev/" + fakeRepo + "/test/e2e", []string{"alice", "bob", "dave"}},
+		{"knative.dev/" + fakeRepo + "/test/e2e/autoscale", []string{"alice", "bob", "dave"}},
+		// test/OWNERS has no approvers, so the root OWNERS file is used
+		{"knative.dev/" + fakeRepo + "/test/conformance", []string{"alice", "bob"}},
+		{"unknown", []string{"alice", "bob"}},
+	}
+	for _, test := range tests {
+		got, err := or.getApprovers(fakeOrg, fakeRepo, &TestStat{Suite: test.suite})
+		if err != nil {
+			t.Fatalf("getApprovers(%q) = %v", test.suite, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("getApprovers(%q) = %v, want %v", test.suite, got, test.want)
+		}
+	}
+
+	if got, err := newOwnersResolver(fg).getApprovers(fakeOrg, "other", &TestStat{}); err != nil || got != nil {
+		t.Errorf("getApprovers() without OWNERS = %v, %v, want none", got, err)
+	}
+	if got, want := mentionUsers([]string{"alice", "bob"}), "@alice @bob"; got != want {
+		t.Errorf("mentionUsers() = %q, want %q", got, want)
+	}
+}
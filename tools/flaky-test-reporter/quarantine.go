@@ -0,0 +1,289 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// quarantine.go maintains skip lists of flaky tests in repos through pull
+// requests, see knative.dev/test-infra/pkg/quarantine for the format
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"knative.dev/test-infra/pkg/ghutil"
+	"knative.dev/test-infra/pkg/helpers"
+	"knative.dev/test-infra/pkg/prow"
+	"knative.dev/test-infra/pkg/quarantine"
+)
+
+const (
+	quarantineBranchPrefix  = "auto-quarantine-"
+	quarantineTitle         = "[Auto] Update quarantined flaky tests"
+	defaultQuarantineBranch = "main"
+	defaultQuarantineDays   = 14
+)
+
+// skipList identifies the skip list of a repo
+type skipList struct {
+	org  string
+	repo string
+	file string
+	base string // branch the file is read from and pull requests target
+	days int
+}
+
+// getSkipLists groups RepoData by the skip list configured for their jobs
+func getSkipLists(repoDataAll []RepoData) map[skipList][]RepoData {
+	skipLists := make(map[skipList][]RepoData)
+	for _, rd := range repoDataAll {
+		qc := rd.Config.Quarantine
+		if qc == nil || qc.File == "" {
+			continue
+		}
+		sl := skipList{org: rd.Config.Org, repo: rd.Config.Repo, file: qc.File, base: qc.Branch, days: qc.Days}
+		if sl.base == "" {
+			sl.base = defaultQuarantineBranch
+		}
+		if sl.days == 0 {
+			sl.days = defaultQuarantineDays
+		}
+		skipLists[sl] = append(skipLists[sl], rd)
+	}
+	return skipLists
+}
+
+// entryMatches reports if a skip list entry is for the test
+func entryMatches(e quarantine.Entry, ts *TestStat) bool {
+	if e.Suite != "" && ts.Suite != "" && e.Suite != ts.Suite {
+		return false
+	}
+	name := ts.Case
+	if name == "" {
+		name = ts.TestName
+	}
+	matched, _ := regexp.MatchString(e.Test, name)
+	return matched
+}
+
+// updateSkipList removes the tests that passed again or whose quarantine
+// expired from the skip list, and adds the tests that are flaky now. It
+// returns descriptions of the added and removed tests. Tests are flaky in
+// the jobs of rds, and pass again in the jobs of rds or checks, i.e. the jobs
+// running quarantined tests instead of skipping them.
+func updateSkipList(l *quarantine.List, rds, checks []RepoData, flakyIssues map[string][]flakyIssue, now time.Time, days int) ([]string, []string) {
+	var added, removed []string
+	var kept []quarantine.Entry
+	for _, e := range l.Tests {
+		reason := ""
+		if !now.Before(e.Expires) {
+			reason = "quarantine expired"
+		}
+		for _, rd := range append(append([]RepoData{}, rds...), checks...) {
+			for _, ts := range rd.TestStats {
+				if reason == "" && ts.isPassed() && entryMatches(e, ts) {
+					reason = fmt.Sprintf("passed in the latest runs of '%s'", rd.Config.Name)
+				}
+			}
+		}
+		if reason != "" {
+			removed = append(removed, fmt.Sprintf("`%s`: %s", e.Test, reason))
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	l.Tests = kept
+
+	for _, rd := range rds {
+		// Flaky tests are most likely caused by something else, see
+		// processGithubIssuesForRepo
+		if flakyRateAboveThreshold(rd) {
+			continue
+		}
+		for _, testName := range getFlakyTests(rd) {
+			ts := rd.TestStats[testName]
+			quarantined := false
+			for _, e := range l.Tests {
+				quarantined = quarantined || entryMatches(e, ts)
+			}
+			if quarantined {
+				continue
+			}
+			name := ts.Case
+			if name == "" {
+				name = ts.TestName
+			}
+			e := quarantine.Entry{
+				Suite:   ts.Suite,
+				Test:    quarantine.TestPattern(name),
+				Expires: now.AddDate(0, 0, days).UTC().Truncate(24 * time.Hour),
+			}
+			for _, fi := range flakyIssues[getIdentityForTest(testName, rd.Config.Repo, rd.Config.Name)] {
+				e.Issue = fi.issue.GetHTMLURL()
+			}
+			l.Tests = append(l.Tests, e)
+			added = append(added, fmt.Sprintf("`%s`: flaky in '%s' %s", testName, rd.Config.Name, e.Issue))
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// getRunQuarantinedJobs returns the jobs set as RunQuarantinedJob by the
+// jobs of a skip list
+func getRunQuarantinedJobs(sl skipList, rds, repoDataAll []RepoData) []RepoData {
+	var checks []RepoData
+	seen := make(map[string]bool)
+	for _, rd := range rds {
+		name := rd.Config.Quarantine.RunQuarantinedJob
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		found := false
+		for _, check := range repoDataAll {
+			if check.Config.Name == name && check.Config.Org == sl.org && check.Config.Repo == sl.repo {
+				found = true
+				checks = append(checks, check)
+			}
+		}
+		if !found {
+			log.Printf("no results of job '%s' running the quarantined tests of '%s/%s'", name, sl.org, sl.repo)
+		}
+	}
+	return checks
+}
+
+// createQuarantinePRBody describes the changes of the skip list
+func createQuarantinePRBody(sl skipList, added, removed []string) string {
+	body := fmt.Sprintf("Auto-generated by flaky-test-reporter, updating the flaky tests quarantined in `%s`.\n", sl.file)
+	if len(added) > 0 {
+		body += fmt.Sprintf("\nQuarantined for %d days:\n- %s\n", sl.days, strings.Join(added, "\n- "))
+	}
+	if len(removed) > 0 {
+		body += fmt.Sprintf("\nNo longer quarantined:\n- %s\n", strings.Join(removed, "\n- "))
+	}
+	return body
+}
+
+// writeSkipListArtifact writes the skip list under the local artifacts
+// directory of the repo
+func writeSkipListArtifact(sl skipList, contents []byte) error {
+	dir := path.Join(prow.GetLocalArtifactsDir(), sl.repo)
+	if err := helpers.CreateDir(dir); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(dir, path.Base(sl.file)), contents, 0644)
+}
+
+// updateQuarantineForRepo updates a skip list, and opens a pull request with
+// the changes, or updates the pull request already open. The skip list is
+// rebuilt from base on every run, so the pull request is closed once there
+// is nothing left to change, i.e. the tests it quarantined passed again.
+func (gih *GithubIssueHandler) updateQuarantineForRepo(sl skipList, rds, checks []RepoData, flakyIssues map[string][]flakyIssue, dryrun bool) error {
+	current, sha, err := gih.client.GetFile(sl.org, sl.repo, sl.file, sl.base)
+	if err != nil {
+		return fmt.Errorf("failed reading skip list '%s' of '%s/%s': %v", sl.file, sl.org, sl.repo, err)
+	}
+	l, err := quarantine.Parse(current)
+	if err != nil {
+		return fmt.Errorf("failed parsing skip list '%s' of '%s/%s': %v", sl.file, sl.org, sl.repo, err)
+	}
+	added, removed := updateSkipList(l, rds, checks, flakyIssues, time.Now(), sl.days)
+	contents, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := writeSkipListArtifact(sl, contents); err != nil {
+		return err
+	}
+	branch := quarantineBranchPrefix + sl.base
+	head := fmt.Sprintf("%s:%s", sl.org, branch)
+	if len(added) == 0 && len(removed) == 0 {
+		log.Printf("skip list '%s' of '%s/%s' is up to date", sl.file, sl.org, sl.repo)
+		return gih.closeQuarantinePR(sl, head, dryrun)
+	}
+
+	body := createQuarantinePRBody(sl, added, removed)
+	return helpers.Run(
+		fmt.Sprintf("updating skip list '%s' of '%s/%s':\n%s", sl.file, sl.org, sl.repo, body),
+		func() error {
+			// The branch is reset to base, so the file is the same as on base
+			if err := gih.client.CreateBranch(sl.org, sl.repo, branch, sl.base); err != nil {
+				return err
+			}
+			if err := gih.client.UpdateFile(sl.org, sl.repo, branch, sl.file, quarantineTitle, contents, sha); err != nil {
+				return err
+			}
+			prs, err := gih.client.ListPullRequests(sl.org, sl.repo, head, sl.base)
+			if err != nil {
+				return err
+			}
+			for _, pr := range prs {
+				if pr.GetState() == string(ghutil.PullRequestOpenState) {
+					_, err := gih.client.EditPullRequest(sl.org, sl.repo, pr.GetNumber(), quarantineTitle, body)
+					return err
+				}
+			}
+			_, err = gih.client.CreatePullRequest(sl.org, sl.repo, head, sl.base, quarantineTitle, body)
+			return err
+		},
+		dryrun,
+	)
+}
+
+// closeQuarantinePR closes the open pull request updating a skip list
+func (gih *GithubIssueHandler) closeQuarantinePR(sl skipList, head string, dryrun bool) error {
+	prs, err := gih.client.ListPullRequests(sl.org, sl.repo, head, sl.base)
+	if err != nil {
+		return err
+	}
+	for _, pr := range prs {
+		if pr.GetState() != string(ghutil.PullRequestOpenState) {
+			continue
+		}
+		if err := helpers.Run(
+			fmt.Sprintf("closing pull request %d of '%s/%s', nothing left to change", pr.GetNumber(), sl.org, sl.repo),
+			func() error {
+				return gih.client.ClosePullRequest(sl.org, sl.repo, pr.GetNumber())
+			},
+			dryrun,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processQuarantine updates the skip lists of all repos configured with
+// one, based on the results of their jobs
+func (gih *GithubIssueHandler) processQuarantine(repoDataAll []RepoData, flakyIssues map[string][]flakyIssue, dryrun bool) error {
+	var allErrs []error
+	for sl, rds := range getSkipLists(repoDataAll) {
+		checks := getRunQuarantinedJobs(sl, rds, repoDataAll)
+		if err := gih.updateQuarantineForRepo(sl, rds, checks, flakyIssues, dryrun); err != nil {
+			log.Println(err)
+			allErrs = append(allErrs, err)
+		}
+	}
+	return helpers.CombineErrors(allErrs)
+}
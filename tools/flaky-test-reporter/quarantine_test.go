@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"knative.dev/test-infra/pkg/ghutil"
+	"knative.dev/test-infra/pkg/ghutil/fakeghutil"
+	"knative.dev/test-infra/pkg/quarantine"
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+func newQuarantineRepoData() RepoData {
+	return RepoData{
+		Config: config.JobConfig{
+			Name:       "continuous",
+			Org:        fakeOrg,
+			Repo:       fakeRepo,
+			Quarantine: &config.QuarantineConfig{File: "test/quarantine.yaml"},
+		},
+		TestStats: map[string]*TestStat{
+			"e2e.TestFlaky":     {TestName: "e2e.TestFlaky", Suite: "e2e", Case: "TestFlaky", Passed: []int{1, 2, 3, 4}, Failed: []int{5}},
+			"e2e.TestFixed":     {TestName: "e2e.TestFixed", Suite: "e2e", Case: "TestFixed", Passed: []int{1, 2, 3, 4, 5}},
+			"e2e.TestSkipped":   {TestName: "e2e.TestSkipped", Suite: "e2e", Case: "TestSkipped", Skipped: []int{1, 2, 3, 4, 5}},
+			"e2e.TestStable1":   {TestName: "e2e.TestStable1", Suite: "e2e", Case: "TestStable1", Passed: []int{1, 2, 3, 4, 5}},
+			"e2e.TestStable2":   {TestName: "e2e.TestStable2", Suite: "e2e", Case: "TestStable2", Passed: []int{1, 2, 3, 4, 5}},
+			"other.TestFlaky/a": {TestName: "other.TestFlaky/a", Suite: "other", Case: "TestFlaky/a", Passed: []int{1, 2, 3, 4, 5}},
+		},
+	}
+}
+
+func TestUpdateSkipList(t *testing.T) {
+	setBuildsCount(t, 5)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := &quarantine.List{Tests: []quarantine.Entry{
+		{Suite: "e2e", Test: quarantine.TestPattern("TestFixed"), Expires: now.Add(time.Hour)},
+		// Skipped as quarantined, kept until it expires
+		{Suite: "e2e", Test: quarantine.TestPattern("TestSkipped"), Expires: now.Add(time.Hour)},
+		{Suite: "e2e", Test: quarantine.TestPattern("TestGone"), Expires: now},
+	}}
+	rd := newQuarantineRepoData()
+	issueURL := "https://github.com/fakeorg/fakerepo/issues/1"
+	flakyIssues := map[string][]flakyIssue{
+		getIdentityForTest("e2e.TestFlaky", fakeRepo, "continuous"): {{issue: &github.Issue{HTMLURL: &issueURL}}},
+	}
+
+	added, removed := updateSkipList(l, []RepoData{rd}, nil, flakyIssues, now, 14)
+	if len(added) != 1 || !strings.Contains(added[0], "e2e.TestFlaky") {
+		t.Errorf("updateSkipList() added = %v, want e2e.TestFlaky", added)
+	}
+	if len(removed) != 2 || !strings.Contains(removed[0], "TestFixed") || !strings.Contains(removed[1], "TestGone") {
+		t.Errorf("updateSkipList() removed = %v, want TestFixed and TestGone", removed)
+	}
+
+	var tests []string
+	for _, e := range l.Tests {
+		tests = append(tests, e.Suite+" "+e.Test)
+		if e.Test == quarantine.TestPattern("TestFlaky") {
+			if e.Issue != issueURL {
+				t.Errorf("Issue = %q, want %q", e.Issue, issueURL)
+			}
+			if want := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC); !e.Expires.Equal(want) {
+				t.Errorf("Expires = %v, want %v", e.Expires, want)
+			}
+		}
+	}
+	if want := "e2e ^TestSkipped$,e2e ^TestFlaky$"; strings.Join(tests, ",") != want {
+		t.Errorf("skip list = %v, want %s", tests, want)
+	}
+
+	// Nothing changes in the next run
+	if added, removed := updateSkipList(l, []RepoData{rd}, nil, flakyIssues, now, 14); len(added)+len(removed) != 0 {
+		t.Errorf("updateSkipList() again = %v, %v, want no change", added, removed)
+	}
+}
+
+func TestProcessQuarantine(t *testing.T) {
+	t.Setenv("ARTIFACTS", t.TempDir())
+	setBuildsCount(t, 5)
+	fgih := getFakeGithubIssueHandler()
+	fg := fgih.client.(*fakeghutil.FakeGithubClient)
+	fg.PullRequests[fakeRepo] = make(map[int]*github.PullRequest)
+	repoDataAll := []RepoData{newQuarantineRepoData()}
+	file := "test/quarantine.yaml"
+	branch := quarantineBranchPrefix + defaultQuarantineBranch
+
+	if err := fgih.processQuarantine(repoDataAll, nil, false); err != nil {
+		t.Fatalf("processQuarantine() = %v", err)
+	}
+	if len(fg.PullRequests[fakeRepo]) != 1 {
+		t.Fatalf("processQuarantine() opened %d pull requests, want 1", len(fg.PullRequests[fakeRepo]))
+	}
+	if contents := fg.FileContents[fakeRepo][defaultQuarantineBranch][file]; contents != nil {
+		t.Errorf("skip list on %s = %q, want it unchanged", defaultQuarantineBranch, contents)
+	}
+	l, err := quarantine.Parse(fg.FileContents[fakeRepo][branch][file])
+	if err != nil || len(l.Tests) != 1 {
+		t.Fatalf("skip list on %s = %v, %v, want TestFlaky only", branch, l, err)
+	}
+	artifact, err := ioutil.ReadFile(filepath.Join(os.Getenv("ARTIFACTS"), fakeRepo, "quarantine.yaml"))
+	if err != nil || !strings.Contains(string(artifact), "^TestFlaky$") {
+		t.Errorf("skip list artifact = %q, %v, want TestFlaky", artifact, err)
+	}
+
+	// The test passes again before the pull request is merged, the pull
+	// request is closed as nothing is left to change
+	repoDataAll[0].TestStats["e2e.TestFlaky"].Failed = nil
+	repoDataAll[0].TestStats["e2e.TestFlaky"].Passed = []int{1, 2, 3, 4, 5}
+	if err := fgih.processQuarantine(repoDataAll, nil, false); err != nil {
+		t.Fatalf("processQuarantine() = %v", err)
+	}
+	for _, pr := range fg.PullRequests[fakeRepo] {
+		if pr.GetState() != string(ghutil.PullRequestCloseState) {
+			t.Errorf("pull request state = %q, want closed", pr.GetState())
+		}
+	}
+
+	// The test passes again after the pull request is merged, a pull request
+	// removes it
+	fg.FileContents[fakeRepo][defaultQuarantineBranch] = map[string][]byte{file: fg.FileContents[fakeRepo][branch][file]}
+	if err := fgih.processQuarantine(repoDataAll, nil, false); err != nil {
+		t.Fatalf("processQuarantine() = %v", err)
+	}
+	if len(fg.PullRequests[fakeRepo]) != 2 {
+		t.Fatalf("processQuarantine() opened %d pull requests, want 2", len(fg.PullRequests[fakeRepo]))
+	}
+	for _, pr := range fg.PullRequests[fakeRepo] {
+		if pr.GetState() == string(ghutil.PullRequestOpenState) && !strings.Contains(pr.GetBody(), "No longer quarantined") {
+			t.Errorf("pull request body = %q, want removed tests", pr.GetBody())
+		}
+	}
+	if l, err := quarantine.Parse(fg.FileContents[fakeRepo][branch][file]); err != nil || len(l.Tests) != 0 {
+		t.Errorf("skip list on %s = %v, %v, want no tests", branch, l, err)
+	}
+}
+
+func TestUpdateSkipListRunQuarantinedJob(t *testing.T) {
+	setBuildsCount(t, 5)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := &quarantine.List{Tests: []quarantine.Entry{
+		{Suite: "e2e", Test: quarantine.TestPattern("TestSkipped"), Expires: now.Add(time.Hour)},
+		{Suite: "e2e", Test: quarantine.TestPattern("TestStillFlaky"), Expires: now.Add(time.Hour)},
+	}}
+	rd := newQuarantineRepoData()
+	rd.Config.Quarantine.RunQuarantinedJob = "quarantined"
+	check := RepoData{
+		Config: config.JobConfig{Name: "quarantined", Org: fakeOrg, Repo: fakeRepo},
+		TestStats: map[string]*TestStat{
+			"e2e.TestSkipped":    {TestName: "e2e.TestSkipped", Suite: "e2e", Case: "TestSkipped", Passed: []int{1, 2, 3, 4, 5}},
+			"e2e.TestStillFlaky": {TestName: "e2e.TestStillFlaky", Suite: "e2e", Case: "TestStillFlaky", Passed: []int{1, 2, 3, 4}, Failed: []int{5}},
+		},
+	}
+	other := RepoData{Config: config.JobConfig{Name: "quarantined", Org: fakeOrg, Repo: "other"}}
+
+	sl := getSkipLists([]RepoData{rd})
+	if len(sl) != 1 {
+		t.Fatalf("getSkipLists() = %v, want 1 skip list", sl)
+	}
+	for sl, rds := range sl {
+		checks := getRunQuarantinedJobs(sl, rds, []RepoData{rd, check, other})
+		if len(checks) != 1 || checks[0].Config.Repo != fakeRepo {
+			t.Fatalf("getRunQuarantinedJobs() = %v, want job 'quarantined' of %s", checks, fakeRepo)
+		}
+		// TestSkipped is skipped by rd and passes when run by check
+		_, removed := updateSkipList(l, rds, checks, nil, now, 14)
+		if len(removed) != 1 || !strings.Contains(removed[0], "TestSkipped") || !strings.Contains(removed[0], "'quarantined'") {
+			t.Errorf("updateSkipList() removed = %v, want TestSkipped passed in 'quarantined'", removed)
+		}
+	}
+	var tests []string
+	for _, e := range l.Tests {
+		tests = append(tests, e.Test)
+	}
+	if want := "^TestStillFlaky$,^TestFlaky$"; strings.Join(tests, ",") != want {
+		t.Errorf("skip list = %v, want %s", tests, want)
+	}
+}
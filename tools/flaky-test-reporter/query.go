@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// query.go queries the history store for the flakiness of tests over time
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"knative.dev/test-infra/tools/flaky-test-reporter/history"
+)
+
+const queryCommand = "query"
+
+// runQuery runs the query command with its arguments, printing either the
+// flakiness of a test, or the flakiest tests of each repo
+func runQuery(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(queryCommand, flag.ContinueOnError)
+	hf := addHistoryFlags(fs)
+	repo := fs.String("repo", "", "only query tests of this repo")
+	test := fs.String("test", "", "full name of the test to query, i.e. 'test/e2e.TestFoo', instead of the flakiest tests")
+	days := fs.Int("days", 90, "count of days to query")
+	top := fs.Int("top", 20, "count of flakiest tests to list per repo")
+	output := fs.String("output", "text", "output format, one of text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("invalid --output %q, must be one of text or json", *output)
+	}
+
+	store, err := hf.newStore()
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return errors.New("one of --history-file or --history-db-host is required")
+	}
+	defer store.Close()
+
+	records, err := store.Records(history.Query{
+		Repo:  *repo,
+		Test:  *test,
+		Since: time.Now().AddDate(0, 0, -*days),
+	})
+	if err != nil {
+		return err
+	}
+	var summaries []history.TestSummary
+	if *test != "" {
+		summaries = history.Summarize(records)
+	} else {
+		summaries = history.Flakiest(records, *top)
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summaries)
+	}
+	if len(summaries) == 0 {
+		_, err := fmt.Fprintf(out, "No result found in the last %d days\n", *days)
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "REPO\tTEST\tRUNS\tFAILURES\tSKIPPED\tFAILURE RATE")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1f%%\n", s.Repo, s.Test, s.Runs, s.Failures, s.Skipped, s.Rate*100)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// report.go creates the HTML and Markdown flakiness reports of a job, with
+// the results of tests across the builds scanned
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"knative.dev/test-infra/pkg/gcs"
+	"knative.dev/test-infra/pkg/junit"
+)
+
+// reportBuild is a column of the grid of the report
+type reportBuild struct {
+	ID int
+	// URL is the link to the build, and ArtifactsURL the link to its
+	// artifacts in the GCS console if any. Both come from the result source,
+	// so they are trusted as links, including file:// ones.
+	URL          template.URL
+	ArtifactsURL template.URL
+	Outage       bool // excluded as an infrastructure failure
+}
+
+// reportTest is a row of the grid of the report
+type reportTest struct {
+	Name     string
+	Status   string
+	Runs     int // passed or failed
+	Failures int
+	Rate     float64 // failure rate
+	Score    float64 // flakiness score
+	Results  []string
+}
+
+// report is the data rendered in the reports of a job
+type report struct {
+	Job        string
+	Repo       string
+	Time       string
+	TotalTests int
+	FlakyTests int
+	FlakyRate  float64
+	Builds     []reportBuild
+	// Tests are the tests that didn't pass in all builds, the most flaky first
+	Tests []reportTest
+}
+
+// getReport creates the report of the job of rd. Tests that passed in all
+// builds are only counted, to keep the report readable.
+func getReport(rd RepoData) report {
+	r := report{
+		Job:        rd.Config.Name,
+		Repo:       rd.Config.Repo,
+		TotalTests: len(rd.TestStats),
+		FlakyTests: len(getFlakyTests(rd)),
+		FlakyRate:  float64(getFlakyRate(rd)),
+	}
+	if rd.LastBuildStartTime != nil {
+		r.Time = time.Unix(*rd.LastBuildStartTime, 0).UTC().Format(time.RFC3339)
+	}
+	outages := make(map[int]bool)
+	for _, ob := range rd.OutageBuilds {
+		outages[ob.BuildID] = true
+	}
+	// Outage builds are listed so that it's clear why their results are missing
+	var buildIDs []int
+	buildIDs = append(buildIDs, rd.BuildIDs...)
+	for buildID := range outages {
+		buildIDs = append(buildIDs, buildID)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(buildIDs)))
+	for _, buildID := range buildIDs {
+		rb := reportBuild{ID: buildID, URL: template.URL(rd.getBuildURL(buildID)), Outage: outages[buildID]}
+		if gsURL, ok := rd.BuildArtifactsURLs[buildID]; ok {
+			if consoleURL, err := gcs.GetConsoleURL(gsURL); err != nil {
+				log.Printf("failed creating console URL of '%s': %v", gsURL, err)
+			} else {
+				rb.ArtifactsURL = template.URL(consoleURL)
+			}
+		}
+		r.Builds = append(r.Builds, rb)
+	}
+
+	for testName, ts := range rd.TestStats {
+		if len(ts.Failed) == 0 && !ts.isFlakyInBuild() {
+			continue
+		}
+		rt := reportTest{
+			Name:     testName,
+			Status:   ts.getTestStatus(),
+			Runs:     len(ts.Passed) + len(ts.Failed),
+			Failures: len(ts.Failed),
+		}
+		if rt.Runs > 0 {
+			rt.Rate = float64(rt.Failures) / float64(rt.Runs)
+		}
+		if ts.Score != nil {
+			rt.Score = ts.Score.Score
+		}
+		for _, rb := range r.Builds {
+			rt.Results = append(rt.Results, getReportResult(ts, rb))
+		}
+		r.Tests = append(r.Tests, rt)
+	}
+	sort.Slice(r.Tests, func(i, j int) bool {
+		if r.Tests[i].Score != r.Tests[j].Score {
+			return r.Tests[i].Score > r.Tests[j].Score
+		}
+		if r.Tests[i].Rate != r.Tests[j].Rate {
+			return r.Tests[i].Rate > r.Tests[j].Rate
+		}
+		return r.Tests[i].Name < r.Tests[j].Name
+	})
+	return r
+}
+
+// getReportResult returns the result of a test in a build of the report, one
+// of junit statuses, flakyInBuildStatus, or empty if the test didn't run
+func getReportResult(ts *TestStat, rb reportBuild) string {
+	if rb.Outage {
+		return ""
+	}
+	if intSliceContains(ts.FlakyInBuild, rb.ID) {
+		return flakyInBuildStatus
+	}
+	return string(ts.getResult(rb.ID))
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Flaky tests of {{.Job}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; }
+th.sortable { cursor: pointer; background: #eee; }
+td.num { text-align: right; }
+td.passed { background: #9c6; }
+td.failed { background: #e66; }
+td.skipped { background: #ddd; }
+td.FlakyInBuild { background: #fc6; }
+th.outage { background: #999; }
+</style>
+</head>
+<body>
+<h1>Flaky tests of job {{.Job}} in repo {{.Repo}}</h1>
+<p>As of {{.Time}}: {{.FlakyTests}} flaky tests out of {{.TotalTests}}, flaky rate {{percent .FlakyRate}}.
+Only tests that failed or passed on retry in any build are listed, click on a column header to sort by it.</p>
+<table id="tests">
+<thead>
+<tr>
+<th class="sortable" data-type="string">Test</th>
+<th class="sortable" data-type="string">Status</th>
+<th class="sortable" data-type="number">Failures</th>
+<th class="sortable" data-type="number">Runs</th>
+<th class="sortable" data-type="number">Failure rate</th>
+<th class="sortable" data-type="number">Flakiness score</th>
+{{- range .Builds}}
+<th{{if .Outage}} class="outage" title="excluded as an infrastructure failure"{{end}}><a href="{{.URL}}">{{.ID}}</a>{{if .ArtifactsURL}} <a href="{{.ArtifactsURL}}">artifacts</a>{{end}}</th>
+{{- end}}
+</tr>
+</thead>
+<tbody>
+{{- range .Tests}}
+<tr>
+<td>{{.Name}}</td>
+<td>{{.Status}}</td>
+<td class="num">{{.Failures}}</td>
+<td class="num">{{.Runs}}</td>
+<td class="num" data-value="{{.Rate}}">{{percent .Rate}}</td>
+<td class="num" data-value="{{.Score}}">{{printf "%.2f" .Score}}</td>
+{{- range .Results}}
+<td class="{{.}}">{{symbol .}}</td>
+{{- end}}
+</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.querySelectorAll("#tests th.sortable").forEach(function(th, column) {
+  var ascending = false;
+  th.addEventListener("click", function() {
+    ascending = !ascending;
+    var tbody = document.querySelector("#tests tbody");
+    var value = function(row) {
+      var td = row.children[column];
+      var v = td.dataset.value !== undefined ? td.dataset.value : td.textContent;
+      return th.dataset.type === "number" ? parseFloat(v) : v;
+    };
+    Array.from(tbody.rows).sort(function(a, b) {
+      var va = value(a), vb = value(b);
+      var res = va < vb ? -1 : (va > vb ? 1 : 0);
+      return ascending ? res : -res;
+    }).forEach(function(row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`
+
+var reportFuncs = template.FuncMap{
+	"percent": func(rate float64) string { return fmt.Sprintf("%.2f%%", rate*100) },
+	"symbol":  reportSymbol,
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(reportFuncs).Parse(htmlReportTemplate))
+
+// reportSymbol returns the symbol of a result in the grid
+func reportSymbol(result string) string {
+	switch result {
+	case string(junit.Passed):
+		return "✔"
+	case string(junit.Failed):
+		return "✖"
+	case flakyInBuildStatus:
+		return "↻"
+	case string(junit.Skipped):
+		return "◻"
+	default:
+		return ""
+	}
+}
+
+// writeHTMLReport writes the self-contained HTML report
+func writeHTMLReport(w io.Writer, r report) error {
+	return htmlReport.Execute(w, r)
+}
+
+// writeMarkdownReport writes the Markdown report, with the same grid as the
+// HTML report without sorting
+func writeMarkdownReport(w io.Writer, r report) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Flaky tests of job %s in repo %s\n\n", r.Job, r.Repo)
+	fmt.Fprintf(&b, "As of %s: %d flaky tests out of %d, flaky rate %.2f%%.\n\n",
+		r.Time, r.FlakyTests, r.TotalTests, r.FlakyRate*100)
+	if len(r.Tests) == 0 {
+		b.WriteString("All tests passed.\n")
+		_, err := w.Write(b.Bytes())
+		return err
+	}
+	b.WriteString("| Test | Status | Failures | Runs | Failure rate | Flakiness score |")
+	for _, rb := range r.Builds {
+		fmt.Fprintf(&b, " [%d](%s) |", rb.ID, rb.URL)
+	}
+	b.WriteString("\n|---|---|--:|--:|--:|--:|")
+	b.WriteString(strings.Repeat(":-:|", len(r.Builds)))
+	b.WriteString("\n")
+	for _, rt := range r.Tests {
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %.2f%% | %.2f |",
+			strings.ReplaceAll(rt.Name, "|", "\\|"), rt.Status, rt.Failures, rt.Runs, rt.Rate*100, rt.Score)
+		for _, result := range rt.Results {
+			fmt.Fprintf(&b, " %s |", reportSymbol(result))
+		}
+		b.WriteString("\n")
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"reflect"
+	"strings"
+	"testing"
+
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+func getReportRepoData() RepoData {
+	startTime := int64(1767225600)
+	return RepoData{
+		Config:   config.JobConfig{Name: "ci-serving-continuous", Repo: "serving"},
+		BuildIDs: []int{3, 2, 1},
+		BuildArtifactsURLs: map[int]string{
+			3: "gs://knative-prow/logs/ci-serving-continuous/3",
+		},
+		OutageBuilds: []OutageBuild{{BuildID: 4, Failed: 100, Ran: 100}},
+		TestStats: map[string]*TestStat{
+			"e2e.TestPasses": {TestName: "e2e.TestPasses", Passed: []int{1, 2, 3}},
+			"e2e.TestFlaky": {TestName: "e2e.TestFlaky", Passed: []int{1, 3}, Failed: []int{2},
+				Score: &FlakinessScore{Score: 0.5}},
+			"e2e.TestRetried": {TestName: "e2e.TestRetried", Passed: []int{1, 2, 3}, FlakyInBuild: []int{3},
+				Score: &FlakinessScore{Score: 0.2}},
+			"e2e.TestFails|x": {TestName: "e2e.TestFails|x", Skipped: []int{1}, Failed: []int{2, 3}},
+		},
+		LastBuildStartTime: &startTime,
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	r := getReport(getReportRepoData())
+
+	var builds []int
+	for _, rb := range r.Builds {
+		builds = append(builds, rb.ID)
+	}
+	if want := []int{4, 3, 2, 1}; !reflect.DeepEqual(builds, want) {
+		t.Errorf("getReport() builds = %v, want %v", builds, want)
+	}
+	if !r.Builds[0].Outage || r.Builds[1].Outage {
+		t.Errorf("getReport() outage builds = %v, want only build 4", r.Builds)
+	}
+	if want := template.URL("https://console.cloud.google.com/storage/browser/knative-prow/logs/ci-serving-continuous/3"); r.Builds[1].ArtifactsURL != want {
+		t.Errorf("getReport() artifacts URL = %q, want %q", r.Builds[1].ArtifactsURL, want)
+	}
+	if r.Builds[2].ArtifactsURL != "" {
+		t.Errorf("getReport() artifacts URL = %q, want none", r.Builds[2].ArtifactsURL)
+	}
+
+	var tests []string
+	for _, rt := range r.Tests {
+		tests = append(tests, rt.Name)
+	}
+	if want := []string{"e2e.TestFlaky", "e2e.TestRetried", "e2e.TestFails|x"}; !reflect.DeepEqual(tests, want) {
+		t.Errorf("getReport() tests = %v, want %v", tests, want)
+	}
+	if want := []string{"", flakyInBuildStatus, "passed", "passed"}; !reflect.DeepEqual(r.Tests[1].Results, want) {
+		t.Errorf("getReport() results = %v, want %v", r.Tests[1].Results, want)
+	}
+	if r.TotalTests != 4 || r.Tests[2].Rate != 1 || r.Tests[2].Runs != 2 {
+		t.Errorf("getReport() = %+v, want 4 tests and a failure rate of 1 over 2 runs for e2e.TestFails|x", r)
+	}
+}
+
+func TestWriteReports(t *testing.T) {
+	r := getReport(getReportRepoData())
+
+	var html bytes.Buffer
+	if err := writeHTMLReport(&html, r); err != nil {
+		t.Fatalf("writeHTMLReport() = %v", err)
+	}
+	for _, want := range []string{
+		`<td>e2e.TestFlaky</td>`,
+		`<td class="num" data-value="0.5">0.50</td>`,
+		`<a href="https://console.cloud.google.com/storage/browser/knative-prow/logs/ci-serving-continuous/3">artifacts</a>`,
+		`<td class="FlakyInBuild">↻</td>`,
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("writeHTMLReport() = %s, want it to contain %q", html.String(), want)
+		}
+	}
+
+	var md bytes.Buffer
+	if err := writeMarkdownReport(&md, r); err != nil {
+		t.Fatalf("writeMarkdownReport() = %v", err)
+	}
+	for _, want := range []string{
+		"| e2e.TestFlaky | " + r.Tests[0].Status + " | 1 | 3 | 33.33% | 0.50 |  | ✔ | ✖ | ✔ |",
+		"| e2e.TestFails\\|x |",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("writeMarkdownReport() = %s, want it to contain %q", md.String(), want)
+		}
+	}
+}
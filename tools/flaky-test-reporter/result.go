@@ -19,8 +19,10 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"path"
@@ -36,10 +38,12 @@ import (
 )
 
 const (
-	flakyStatus    = "Flaky"
-	passedStatus   = "Passed"
-	lackDataStatus = "NotEnoughData"
-	failedStatus   = "Failed"
+	flakyStatus        = "Flaky"
+	flakyInBuildStatus = "FlakyInBuild"
+	passedStatus       = "Passed"
+	lackDataStatus     = "NotEnoughData"
+	failedStatus       = "Failed"
+	recoveredStatus    = "Recovered"
 )
 
 // RepoData struct contains all configurations and test results for a repo
@@ -47,6 +51,10 @@ type RepoData struct {
 	Config             config.JobConfig
 	TestStats          map[string]*TestStat // key is test full name
 	BuildIDs           []int                // all build IDs scanned in this run
+	BuildURLs          map[int]string       // links to the builds, key is build ID
+	BuildStartTimes    map[int]int64        // timestamps of the builds, key is build ID
+	BuildArtifactsURLs map[int]string       // gs:// links to the artifacts of the builds, key is build ID
+	OutageBuilds       []OutageBuild        // builds excluded as infrastructure failures
 	LastBuildStartTime *int64               // timestamp, determines how fresh the data is
 }
 
@@ -54,12 +62,26 @@ type RepoData struct {
 // Passed, Skipped and Failed contains buildIDs with corresponding results
 type TestStat struct {
 	TestName string
-	Passed   []int
-	Skipped  []int
-	Failed   []int
+	// Suite and Case are the junit names of the suite and of the test case,
+	// TestName is made of both
+	Suite   string `json:",omitempty"`
+	Case    string `json:",omitempty"`
+	Passed  []int
+	Skipped []int
+	Failed  []int
+	// FlakyInBuild contains buildIDs where the test failed, then passed when
+	// rerun in the same build. These builds are also in Passed.
+	FlakyInBuild []int `json:",omitempty"`
+	// FailureSignatures are the normalized failure messages, key is build ID
+	FailureSignatures map[int]string `json:",omitempty"`
+	// Score is the flakiness score of the test, nil until scored
+	Score *FlakinessScore `json:",omitempty"`
 }
 
 func (ts *TestStat) isFlaky() bool {
+	if ts.Score != nil {
+		return ts.Score.Classification == flakyClass
+	}
 	// This is only responsible for creating and reopening issue,
 	// can be aggressive even when there is not enough runs.
 	// For example  if there are 10 runs, 1 failed, 1 passed, 8 skipped,
@@ -67,10 +89,24 @@ func (ts *TestStat) isFlaky() bool {
 	return len(ts.Failed) > 0 && len(ts.Passed) != 0
 }
 
+// isFlakyInBuild reports if the test only passed on retry in some builds
+func (ts *TestStat) isFlakyInBuild() bool {
+	return len(ts.FlakyInBuild) > 0
+}
+
+func (ts *TestStat) isRecovered() bool {
+	return ts.Score != nil && ts.Score.Classification == recoveredClass
+}
+
+func (ts *TestStat) isConsistentlyFailing() bool {
+	return ts.Score != nil && ts.Score.Classification == failingClass
+}
+
 func (ts *TestStat) isPassed() bool {
 	// This is responsible for marking issue as fixed, needs to be
-	// very strict in terms of runs, so enforcing hasEnoughRuns here
-	return ts.hasEnoughRuns() && len(ts.Failed) == 0
+	// very strict in terms of runs, so enforcing hasEnoughRuns here.
+	// Passing only on retry doesn't count.
+	return ts.hasEnoughRuns() && len(ts.Failed) == 0 && !ts.isFlakyInBuild()
 }
 
 func (ts *TestStat) hasEnoughRuns() bool {
@@ -81,6 +117,12 @@ func (ts *TestStat) getTestStatus() string {
 	switch {
 	case ts.isFlaky():
 		return flakyStatus
+	case ts.isFlakyInBuild():
+		return flakyInBuildStatus
+	case ts.isRecovered():
+		return recoveredStatus
+	case ts.isConsistentlyFailing():
+		return failedStatus
 	case ts.isPassed():
 		return passedStatus
 	case !ts.hasEnoughRuns():
@@ -100,6 +142,17 @@ func getFlakyTests(rd RepoData) []string {
 	return flakyTests
 }
 
+func getFlakyInBuildTests(rd RepoData) []string {
+	var tests []string
+	for testName, ts := range rd.TestStats {
+		if ts.isFlakyInBuild() {
+			tests = append(tests, testName)
+		}
+	}
+	sort.Strings(tests)
+	return tests
+}
+
 func getFlakyRate(rd RepoData) float32 {
 	totalCount := len(rd.TestStats)
 	if 0 == totalCount {
@@ -123,7 +176,7 @@ func flakyRateAboveThreshold(rd RepoData) bool {
 }
 
 // createArtifactForRepo marshals RepoData into json format and stores it in a json file,
-// under local artifacts directory
+// under local artifacts directory, along with the HTML and Markdown reports
 func createArtifactForRepo(rd RepoData) error {
 	artifactsDir := prow.GetLocalArtifactsDir()
 	err := helpers.CreateDir(path.Join(artifactsDir, rd.Config.Repo))
@@ -135,7 +188,24 @@ func createArtifactForRepo(rd RepoData) error {
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(outFilePath, contents, 0644)
+	if err := ioutil.WriteFile(outFilePath, contents, 0644); err != nil {
+		return err
+	}
+
+	r := getReport(rd)
+	for ext, write := range map[string]func(io.Writer, report) error{
+		".html": writeHTMLReport,
+		".md":   writeMarkdownReport,
+	} {
+		var buf bytes.Buffer
+		if err := write(&buf, r); err != nil {
+			return fmt.Errorf("failed creating %s report: %v", ext, err)
+		}
+		if err := ioutil.WriteFile(path.Join(artifactsDir, rd.Config.Repo, rd.Config.Name+ext), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // addSuiteToRepoData adds all testCase from suite into RepoData
@@ -146,16 +216,62 @@ func addSuiteToRepoData(suite *junit.TestSuite, buildID int, rd *RepoData) {
 	for _, testCase := range filterOutParentTests(suite.TestCases) {
 		testFullName := fmt.Sprintf("%s.%s", suite.Name, testCase.Name)
 		if _, ok := rd.TestStats[testFullName]; !ok {
-			rd.TestStats[testFullName] = &TestStat{TestName: testFullName}
+			rd.TestStats[testFullName] = &TestStat{TestName: testFullName, Suite: suite.Name, Case: testCase.Name}
+		}
+		rd.TestStats[testFullName].addResult(buildID, testCase)
+	}
+}
+
+// getResult returns the result of the test in a build, or an empty status if
+// it has none
+func (ts *TestStat) getResult(buildID int) junit.TestStatusEnum {
+	switch {
+	case intSliceContains(ts.Failed, buildID):
+		return junit.Failed
+	case intSliceContains(ts.Passed, buildID):
+		return junit.Passed
+	case intSliceContains(ts.Skipped, buildID):
+		return junit.Skipped
+	}
+	return ""
+}
+
+// addResult records the result of a test case in a build. Jobs rerunning
+// failed tests (i.e. gotestsum --rerun-fails) report several results for the
+// same test in a build: the test passed if any attempt passed, and it's flaky
+// in the build if another attempt failed.
+func (ts *TestStat) addResult(buildID int, testCase junit.TestCase) {
+	status := testCase.GetTestStatus()
+	if status == junit.Failed {
+		if ts.FailureSignatures == nil {
+			ts.FailureSignatures = make(map[int]string)
+		}
+		if _, ok := ts.FailureSignatures[buildID]; !ok {
+			ts.FailureSignatures[buildID] = normalizeFailure(testCase.FailureMessage())
+		}
+	}
+	switch prev := ts.getResult(buildID); {
+	case prev == "":
+	case prev == status, status == junit.Skipped:
+		return
+	case prev == junit.Skipped:
+		ts.Skipped = withoutBuilds(ts.Skipped, sets.NewInt(buildID))
+	default: // both passed and failed attempts
+		if !intSliceContains(ts.FlakyInBuild, buildID) {
+			ts.FlakyInBuild = append(ts.FlakyInBuild, buildID)
 		}
-		switch testCase.GetTestStatus() {
-		case junit.Passed:
-			rd.TestStats[testFullName].Passed = append(rd.TestStats[testFullName].Passed, buildID)
-		case junit.Skipped:
-			rd.TestStats[testFullName].Skipped = append(rd.TestStats[testFullName].Skipped, buildID)
-		case junit.Failed:
-			rd.TestStats[testFullName].Failed = append(rd.TestStats[testFullName].Failed, buildID)
+		if status == junit.Failed {
+			return
 		}
+		ts.Failed = withoutBuilds(ts.Failed, sets.NewInt(buildID))
+	}
+	switch status {
+	case junit.Passed:
+		ts.Passed = append(ts.Passed, buildID)
+	case junit.Skipped:
+		ts.Skipped = append(ts.Skipped, buildID)
+	case junit.Failed:
+		ts.Failed = append(ts.Failed, buildID)
 	}
 }
 
@@ -184,64 +300,91 @@ func filterOutParentTests(originalCases []junit.TestCase) []junit.TestCase {
 
 // TODO: This function has been directly copy-pasted into tools/flaky-test-retryer/log_parser.go
 // Refactor it out into a shared library.
-
 // getCombinedResultsForBuild gets all junit results from a build,
-// and converts each one into a junit TestSuites struct
+// and streams each one into a junit TestSuites struct
 func getCombinedResultsForBuild(build *prow.Build) ([]*junit.TestSuites, error) {
 	var allSuites []*junit.TestSuites
 	for _, artifact := range build.GetArtifacts() {
 		_, fileName := filepath.Split(artifact)
-		if !strings.HasPrefix(fileName, "junit_") || !strings.HasSuffix(fileName, ".xml") {
+		if !isJunitFile(fileName) {
 			continue
 		}
 		relPath, _ := filepath.Rel(build.StoragePath, artifact)
-		contents, err := build.ReadFile(relPath)
+		suites, err := readBuildJunit(build, relPath)
 		if err != nil {
 			return nil, err
 		}
-		// Empty file failed junit unmarshal
-		if len(contents) == 0 || strings.TrimSpace(string(contents)) == "" {
-			continue
-		}
-		if suites, err := junit.UnMarshal(contents); err != nil {
-			return nil, err
-		} else {
+		if suites != nil {
 			allSuites = append(allSuites, suites)
 		}
 	}
 	return allSuites, nil
 }
 
-// collectTestResultsForRepo collects test results, build IDs from all builds,
-// as well as LastBuildStartTime, and stores them in RepoData
-func collectTestResultsForRepo(jc config.JobConfig) (*RepoData, error) {
-	rd := &RepoData{Config: jc}
-	job := prow.NewJob(jc.Name, jc.Type, jc.Org, jc.Repo, 0)
-	if !job.PathExists() {
-		return rd, fmt.Errorf("job path not exist '%s'", jc.Name)
+// readBuildJunit streams a junit file of a build, see readJunit
+func readBuildJunit(build *prow.Build, relPath string) (*junit.TestSuites, error) {
+	r, err := build.NewReader(relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	suites, err := readJunit(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing '%s': %v", relPath, err)
 	}
-	builds := getLatestFinishedBuilds(job, buildsCount)
+	return suites, nil
+}
 
+// collectTestResultsForRepo collects test results, build IDs from all builds
+// listed by the result source, as well as LastBuildStartTime, and stores them
+// in RepoData
+func collectTestResultsForRepo(jc config.JobConfig, src ResultSource) (*RepoData, error) {
+	rd := &RepoData{
+		Config:             jc,
+		BuildURLs:          make(map[int]string),
+		BuildStartTimes:    make(map[int]int64),
+		BuildArtifactsURLs: make(map[int]string),
+	}
+	builds, err := src.LatestBuilds(buildsCount)
+	if err != nil {
+		return nil, err
+	}
 	log.Printf("latest builds: ")
 	for i, build := range builds {
-		log.Printf("\t%d", build.BuildID)
-		rd.BuildIDs = append(rd.BuildIDs, build.BuildID)
+		log.Printf("\t%d", build.ID)
+		rd.BuildIDs = append(rd.BuildIDs, build.ID)
+		rd.BuildURLs[build.ID] = build.URL
+		rd.BuildStartTimes[build.ID] = build.StartTime
+		if build.ArtifactsURL != "" {
+			rd.BuildArtifactsURLs[build.ID] = build.ArtifactsURL
+		}
 		if 0 == i { // This is the latest build as builds are sorted by start time in descending order
-			rd.LastBuildStartTime = build.StartTime
+			startTime := build.StartTime
+			rd.LastBuildStartTime = &startTime
 		}
-		combinedResults, err := getCombinedResultsForBuild(&build)
+		combinedResults, err := src.Results(build)
 		if err != nil {
 			return nil, err
 		}
 		for _, suites := range combinedResults {
 			for _, suite := range suites.Suites {
-				addSuiteToRepoData(&suite, build.BuildID, rd)
+				addSuiteToRepoData(&suite, build.ID, rd)
 			}
 		}
 	}
+	rd.excludeOutageBuilds()
+	rd.scoreTests()
 	return rd, nil
 }
 
+// getBuildURL returns the link to a build, defaulting to the Prow build logs
+func (rd *RepoData) getBuildURL(buildID int) string {
+	if url, ok := rd.BuildURLs[buildID]; ok && url != "" {
+		return url
+	}
+	return fmt.Sprintf("%s%s/%d", jobLogsURL, rd.Config.Name, buildID)
+}
+
 func (rd *RepoData) getResultSliceForTest(testName string) []junit.TestStatusEnum {
 	res := make([]junit.TestStatusEnum, len(rd.BuildIDs))
 	ts := rd.TestStats[testName]
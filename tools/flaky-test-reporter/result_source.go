@@ -0,0 +1,365 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// result_source.go defines where the test results of a job are read from
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+
+	"knative.dev/test-infra/pkg/ghutil"
+	"knative.dev/test-infra/pkg/junit"
+	"knative.dev/test-infra/pkg/prow"
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+// SourceBuild is a finished build of a job listed by a ResultSource
+type SourceBuild struct {
+	ID        int
+	StartTime int64  // timestamp
+	URL       string // link to the build, used in Github issues
+	// ArtifactsURL is the gs:// URL of the artifacts of the build, if stored
+	// in GCS
+	ArtifactsURL string
+}
+
+// ResultSource lists the finished builds of a job and reads their junit
+// results, so that flakiness is analyzed the same way wherever tests ran
+type ResultSource interface {
+	// LatestBuilds returns up to count latest finished builds, the most
+	// recent first
+	LatestBuilds(count int) ([]SourceBuild, error)
+	// Results reads all junit results of a build
+	Results(build SourceBuild) ([]*junit.TestSuites, error)
+}
+
+// newResultSource creates the ResultSource configured for a job, githubToken
+// is only used by the github-actions source
+func newResultSource(jc config.JobConfig, githubToken string) (ResultSource, error) {
+	switch jc.Source {
+	case "", config.ProwSource:
+		if jc.Path != "" {
+			return newProwSource(jc, prow.NewLocalStorage(jc.Path))
+		}
+		return newProwSource(jc, nil)
+	case config.LocalSource:
+		if jc.Path == "" {
+			return nil, fmt.Errorf("path is required for source %q", jc.Source)
+		}
+		return &localSource{dir: jc.Path}, nil
+	case config.GithubActionsSource:
+		if jc.Workflow == "" {
+			return nil, fmt.Errorf("workflow is required for source %q", jc.Source)
+		}
+		ghc, err := ghutil.NewGithubClient(githubToken)
+		if err != nil {
+			return nil, fmt.Errorf("cannot authenticate to github: %v", err)
+		}
+		return &actionsSource{
+			client:   ghc,
+			org:      jc.Org,
+			repo:     jc.Repo,
+			workflow: jc.Workflow,
+			branch:   jc.Branch,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown source %q", jc.Source)
+}
+
+// isJunitFile reports if a file name is a junit result, i.e. junit_*.xml
+func isJunitFile(fileName string) bool {
+	return strings.HasPrefix(fileName, "junit_") && strings.HasSuffix(fileName, ".xml")
+}
+
+// readJunit streams a junit file into a junit TestSuites struct, or returns
+// nil for an empty file. The system-out and system-err of test cases are
+// dropped as they are not used and make up most of large files, so that
+// memory is bounded by the count of test cases rather than the file size.
+func readJunit(r io.Reader) (*junit.TestSuites, error) {
+	suites := &junit.TestSuites{}
+	suiteIndexes := make(map[string]int)
+	err := junit.Stream(r, func(suite *junit.TestSuite, tc *junit.TestCase) error {
+		i, ok := suiteIndexes[suite.Name]
+		if !ok {
+			i = len(suites.Suites)
+			suiteIndexes[suite.Name] = i
+			suites.Suites = append(suites.Suites, *suite)
+		}
+		tc.SystemOut, tc.SystemErr = nil, nil
+		suites.Suites[i].TestCases = append(suites.Suites[i].TestCases, *tc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(suites.Suites) == 0 {
+		return nil, nil
+	}
+	return suites, nil
+}
+
+// prowSource reads the junit artifacts of Prow builds from GCS, or from a
+// local mirror of the bucket
+type prowSource struct {
+	name   string
+	job    *prow.Job
+	local  bool // builds are read from a local mirror, not from GCS
+	builds map[int]*prow.Build
+}
+
+// newProwSource creates a prowSource reading from storage, or from GCS if
+// storage is nil
+func newProwSource(jc config.JobConfig, storage prow.Storage) (*prowSource, error) {
+	job := prow.NewJobWithStorage(storage, jc.Name, jc.Type, jc.Org, jc.Repo, 0)
+	if !job.PathExists() {
+		return nil, fmt.Errorf("job path not exist '%s'", jc.Name)
+	}
+	return &prowSource{name: jc.Name, job: job, local: storage != nil, builds: make(map[int]*prow.Build)}, nil
+}
+
+// LatestBuilds implements ResultSource
+func (ps *prowSource) LatestBuilds(count int) ([]SourceBuild, error) {
+	var builds []SourceBuild
+	for _, build := range getLatestFinishedBuilds(ps.job, count) {
+		build := build
+		ps.builds[build.BuildID] = &build
+		sb := SourceBuild{
+			ID:        build.BuildID,
+			StartTime: *build.StartTime,
+			URL:       fmt.Sprintf("%s%s/%d", jobLogsURL, ps.name, build.BuildID),
+		}
+		if !ps.local {
+			sb.ArtifactsURL = fmt.Sprintf("gs://%s/%s", build.Bucket, build.StoragePath)
+		}
+		builds = append(builds, sb)
+	}
+	return builds, nil
+}
+
+// Results implements ResultSource
+func (ps *prowSource) Results(build SourceBuild) ([]*junit.TestSuites, error) {
+	b, ok := ps.builds[build.ID]
+	if !ok {
+		b = ps.job.NewBuild(build.ID)
+	}
+	return getCombinedResultsForBuild(b)
+}
+
+// localSource reads junit files from a local directory, with one
+// subdirectory per build named after the build ID. The start time of a build
+// is read from its started.json file if any, as in Prow artifacts, or is the
+// modification time of its directory.
+type localSource struct {
+	dir string
+}
+
+// LatestBuilds implements ResultSource
+func (ls *localSource) LatestBuilds(count int) ([]SourceBuild, error) {
+	entries, err := ioutil.ReadDir(ls.dir)
+	if err != nil {
+		return nil, err
+	}
+	var builds []SourceBuild
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		buildID, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		buildDir := filepath.Join(ls.dir, entry.Name())
+		startTime := entry.ModTime().Unix()
+		if contents, err := ioutil.ReadFile(filepath.Join(buildDir, "started.json")); err == nil {
+			var started prow.Started
+			if err := json.Unmarshal(contents, &started); err != nil {
+				return nil, fmt.Errorf("failed parsing started.json of build '%s': %v", buildDir, err)
+			}
+			startTime = started.Timestamp
+		}
+		absDir, err := filepath.Abs(buildDir)
+		if err != nil {
+			return nil, err
+		}
+		builds = append(builds, SourceBuild{
+			ID:        buildID,
+			StartTime: startTime,
+			URL:       "file://" + filepath.ToSlash(absDir),
+		})
+	}
+	sort.Slice(builds, func(i, j int) bool {
+		return builds[i].ID > builds[j].ID
+	})
+	if len(builds) > count {
+		builds = builds[:count]
+	}
+	return builds, nil
+}
+
+// Results implements ResultSource
+func (ls *localSource) Results(build SourceBuild) ([]*junit.TestSuites, error) {
+	var allSuites []*junit.TestSuites
+	buildDir := filepath.Join(ls.dir, strconv.Itoa(build.ID))
+	err := filepath.Walk(buildDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !isJunitFile(info.Name()) {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		suites, err := readJunit(f)
+		if err != nil {
+			return fmt.Errorf("failed parsing '%s': %v", path, err)
+		}
+		if suites != nil {
+			allSuites = append(allSuites, suites)
+		}
+		return nil
+	})
+	return allSuites, err
+}
+
+// actionsClient is the subset of ghutil.GithubClient used by actionsSource
+type actionsClient interface {
+	ListWorkflowRunsByFileName(org, repo, fileName, branch string, page int) ([]*github.WorkflowRun, error)
+	ListWorkflowRunArtifacts(org, repo string, runID int64) ([]*github.Artifact, error)
+	DownloadArtifact(org, repo string, artifactID int64) ([]byte, error)
+}
+
+// actionsSource reads junit files from the artifacts of GitHub Actions
+// workflow runs, each completed run is a build
+type actionsSource struct {
+	client   actionsClient
+	org      string
+	repo     string
+	workflow string
+	branch   string
+}
+
+// LatestBuilds implements ResultSource. Runs that were cancelled or skipped
+// have no results and are not builds, and at most maxRunPages pages of runs
+// are read.
+func (as *actionsSource) LatestBuilds(count int) ([]SourceBuild, error) {
+	var builds []SourceBuild
+	for page := 1; len(builds) < count; page++ {
+		if page > maxRunPages {
+			log.Printf("only %d of %d builds found in the latest %d pages of runs of workflow '%s' of '%s/%s'",
+				len(builds), count, maxRunPages, as.workflow, as.org, as.repo)
+			break
+		}
+		runs, err := as.client.ListWorkflowRunsByFileName(as.org, as.repo, as.workflow, as.branch, page)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) == 0 {
+			break
+		}
+		for _, run := range runs {
+			if len(builds) >= count {
+				break
+			}
+			if !isBuildRun(run) {
+				continue
+			}
+			builds = append(builds, SourceBuild{
+				ID:        int(run.GetID()),
+				StartTime: run.GetCreatedAt().Unix(),
+				URL:       run.GetHTMLURL(),
+			})
+		}
+	}
+	return builds, nil
+}
+
+// isBuildRun reports if a workflow run completed with results, i.e. it
+// wasn't cancelled or skipped
+func isBuildRun(run *github.WorkflowRun) bool {
+	if run.GetStatus() != "completed" {
+		return false
+	}
+	switch run.GetConclusion() {
+	case "success", "failure", "timed_out":
+		return true
+	}
+	return false
+}
+
+// Results implements ResultSource
+func (as *actionsSource) Results(build SourceBuild) ([]*junit.TestSuites, error) {
+	artifacts, err := as.client.ListWorkflowRunArtifacts(as.org, as.repo, int64(build.ID))
+	if err != nil {
+		return nil, err
+	}
+	var allSuites []*junit.TestSuites
+	for _, artifact := range artifacts {
+		if artifact.GetExpired() {
+			continue
+		}
+		contents, err := as.client.DownloadArtifact(as.org, as.repo, artifact.GetID())
+		if err != nil {
+			return nil, err
+		}
+		suites, err := junitFromZip(contents)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading artifact '%s' of run %d: %v", artifact.GetName(), build.ID, err)
+		}
+		allSuites = append(allSuites, suites...)
+	}
+	return allSuites, nil
+}
+
+// junitFromZip reads all junit files from a zip archive
+func junitFromZip(contents []byte) ([]*junit.TestSuites, error) {
+	r, err := zip.NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		return nil, err
+	}
+	var allSuites []*junit.TestSuites
+	for _, f := range r.File {
+		if !isJunitFile(filepath.Base(f.Name)) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		suites, err := readJunit(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing '%s': %v", f.Name, err)
+		}
+		if suites != nil {
+			allSuites = append(allSuites, suites)
+		}
+	}
+	return allSuites, nil
+}
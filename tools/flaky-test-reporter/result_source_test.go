@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"knative.dev/test-infra/pkg/junit"
+	"knative.dev/test-infra/pkg/prow"
+	"knative.dev/test-infra/pkg/prow/fakeprow"
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+const (
+	passedJunit = `<testsuites><testsuite name="e2e"><testcase name="TestA"></testcase><testcase name="TestB"></testcase></testsuite></testsuites>`
+	failedJunit = `<testsuites><testsuite name="e2e"><testcase name="TestA"></testcase><testcase name="TestB"><failure>boom</failure></testcase></testsuite></testsuites>`
+)
+
+func TestLocalSource(t *testing.T) {
+	src := &localSource{dir: "testdata/local"}
+	builds, err := src.LatestBuilds(5)
+	if err != nil {
+		t.Fatalf("LatestBuilds() = %v", err)
+	}
+	var ids []int
+	for _, b := range builds {
+		ids = append(ids, b.ID)
+		if !strings.HasPrefix(b.URL, "file://") {
+			t.Errorf("LatestBuilds() URL = %q, want a file URL", b.URL)
+		}
+	}
+	if want := []int{102, 101}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("LatestBuilds() IDs = %v, want %v", ids, want)
+	}
+	if builds[0].StartTime != 1700003600 {
+		t.Errorf("LatestBuilds() StartTime = %d, want %d", builds[0].StartTime, 1700003600)
+	}
+
+	if builds, _ = src.LatestBuilds(1); len(builds) != 1 || builds[0].ID != 102 {
+		t.Errorf("LatestBuilds(1) = %v, want only build 102", builds)
+	}
+
+	suites, err := src.Results(SourceBuild{ID: 101})
+	if err != nil {
+		t.Fatalf("Results() = %v", err)
+	}
+	if len(suites) != 1 || len(suites[0].Suites) != 1 || len(suites[0].Suites[0].TestCases) != 2 {
+		t.Fatalf("Results() = %v, want one suite with two test cases", suites)
+	}
+}
+
+func TestProwSource(t *testing.T) {
+	jc := config.JobConfig{Name: "ci-knative-serving-e2e", Type: prow.PeriodicJob, Org: "knative", Repo: "serving"}
+	storage := fakeprow.NewFakeStorage()
+	job := prow.NewJob(jc.Name, jc.Type, jc.Org, jc.Repo, 0)
+	build1 := storage.AddBuild(job, 1, 1700000000, 1700000600)
+	storage.AddFile(build1+"/artifacts/junit_e2e.xml", []byte(passedJunit))
+	build2 := storage.AddBuild(job, 2, 1700003600, 1700004200)
+	storage.AddFile(build2+"/artifacts/junit_e2e.xml", []byte(failedJunit))
+	storage.AddFile(build2+"/artifacts/build.log", []byte("not a junit file"))
+	storage.AddBuild(job, 3, 1700007200, 0) // still running
+
+	src, err := newProwSource(jc, storage)
+	if err != nil {
+		t.Fatalf("newProwSource() = %v", err)
+	}
+	builds, err := src.LatestBuilds(5)
+	if err != nil {
+		t.Fatalf("LatestBuilds() = %v", err)
+	}
+	var ids []int
+	for _, b := range builds {
+		ids = append(ids, b.ID)
+		if b.ArtifactsURL != "" {
+			t.Errorf("LatestBuilds() ArtifactsURL = %q, want none for a build out of GCS", b.ArtifactsURL)
+		}
+	}
+	if want := []int{2, 1}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("LatestBuilds() IDs = %v, want %v", ids, want)
+	}
+
+	setBuildsCount(t, 5)
+	rd, err := collectTestResultsForRepo(jc, src)
+	if err != nil {
+		t.Fatalf("collectTestResultsForRepo() = %v", err)
+	}
+	ts := rd.TestStats["e2e.TestB"]
+	if ts == nil || ts.getResult(1) != junit.Passed || ts.getResult(2) != junit.Failed {
+		t.Errorf("TestStats[e2e.TestB] = %+v, want passed in build 1 and failed in build 2", ts)
+	}
+
+	if _, err := newProwSource(config.JobConfig{Name: "missing", Type: prow.PeriodicJob}, storage); err == nil {
+		t.Error("newProwSource() of a missing job = nil error, want an error")
+	}
+}
+
+type fakeActionsClient struct {
+	// pages of workflow runs, the first page first
+	pages     [][]*github.WorkflowRun
+	artifacts map[int64][]*github.Artifact
+	zips      map[int64][]byte
+}
+
+func (f *fakeActionsClient) ListWorkflowRunsByFileName(org, repo, fileName, branch string, page int) ([]*github.WorkflowRun, error) {
+	if page < 1 || page > len(f.pages) {
+		return nil, nil
+	}
+	return f.pages[page-1], nil
+}
+
+func (f *fakeActionsClient) ListWorkflowRunArtifacts(org, repo string, runID int64) ([]*github.Artifact, error) {
+	return f.artifacts[runID], nil
+}
+
+func (f *fakeActionsClient) DownloadArtifact(org, repo string, artifactID int64) ([]byte, error) {
+	if z, ok := f.zips[artifactID]; ok {
+		return z, nil
+	}
+	return nil, fmt.Errorf("artifact %d not found", artifactID)
+}
+
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, contents := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestActionsSource(t *testing.T) {
+	created := github.Timestamp{Time: time.Unix(1700000000, 0)}
+	fake := &fakeActionsClient{
+		pages: [][]*github.WorkflowRun{{
+			{ID: github.Int64(5), Status: github.String("in_progress"), CreatedAt: &created},
+			{ID: github.Int64(4), Status: github.String("completed"), Conclusion: github.String("cancelled"), CreatedAt: &created},
+			{ID: github.Int64(2), Status: github.String("completed"), Conclusion: github.String("success"), CreatedAt: &created, HTMLURL: github.String("https://github.com/org/repo/actions/runs/2")},
+		}, {
+			{ID: github.Int64(3), Status: github.String("completed"), Conclusion: github.String("skipped"), CreatedAt: &created},
+			{ID: github.Int64(1), Status: github.String("completed"), Conclusion: github.String("failure"), CreatedAt: &created, HTMLURL: github.String("https://github.com/org/repo/actions/runs/1")},
+		}},
+		artifacts: map[int64][]*github.Artifact{
+			2: {{ID: github.Int64(20), Name: github.String("results")}, {ID: github.Int64(21), Expired: github.Bool(true)}},
+			1: {{ID: github.Int64(10), Name: github.String("results")}},
+		},
+		zips: map[int64][]byte{
+			20: zipFiles(t, map[string]string{"out/junit_e2e.xml": passedJunit, "out/log.txt": "logs", "junit_empty.xml": ""}),
+			10: zipFiles(t, map[string]string{"junit_e2e.xml": failedJunit}),
+		},
+	}
+	src := &actionsSource{client: fake, org: "org", repo: "repo", workflow: "e2e.yaml"}
+
+	setBuildsCount(t, 5)
+	rd, err := collectTestResultsForRepo(config.JobConfig{Name: "e2e", Repo: "repo"}, src)
+	if err != nil {
+		t.Fatalf("collectTestResultsForRepo() = %v", err)
+	}
+	if want := []int{2, 1}; !reflect.DeepEqual(rd.BuildIDs, want) {
+		t.Errorf("BuildIDs = %v, want %v", rd.BuildIDs, want)
+	}
+	if *rd.LastBuildStartTime != 1700000000 {
+		t.Errorf("LastBuildStartTime = %d, want %d", *rd.LastBuildStartTime, 1700000000)
+	}
+	if got, want := rd.getBuildURL(1), "https://github.com/org/repo/actions/runs/1"; got != want {
+		t.Errorf("getBuildURL() = %q, want %q", got, want)
+	}
+	want := map[string]*TestStat{
+		"e2e.TestA": {TestName: "e2e.TestA", Suite: "e2e", Case: "TestA", Passed: []int{2, 1}},
+		"e2e.TestB": {TestName: "e2e.TestB", Suite: "e2e", Case: "TestB", Passed: []int{2}, Failed: []int{1}, FailureSignatures: map[int]string{1: "boom"}},
+	}
+	for name, ts := range rd.TestStats {
+		if ts.Score == nil {
+			t.Errorf("TestStats[%q] is not scored", name)
+		}
+		ts.Score = nil
+		if !reflect.DeepEqual(ts, want[name]) {
+			t.Errorf("TestStats[%q] = %v, want %v", name, ts, want[name])
+		}
+	}
+}
+
+func TestReadJunit(t *testing.T) {
+	for _, empty := range []string{"", " \n"} {
+		if suites, err := readJunit(strings.NewReader(empty)); suites != nil || err != nil {
+			t.Errorf("readJunit(%q) = %v, %v, want nil, nil", empty, suites, err)
+		}
+	}
+	if _, err := readJunit(strings.NewReader("<testsuites><testsuite>")); err == nil {
+		t.Error("readJunit() of malformed junit = nil error, want an error")
+	}
+
+	contents := `<testsuites>
+<testsuite name="e2e"><testcase name="TestA"><failure>boom</failure><system-out>long output</system-out></testcase></testsuite>
+<testsuite name="unit"><testcase name="TestB"><system-err>long output</system-err></testcase></testsuite>
+<testsuite name="e2e"><testcase name="TestC"></testcase></testsuite>
+</testsuites>`
+	suites, err := readJunit(strings.NewReader(contents))
+	if err != nil {
+		t.Fatalf("readJunit() = %v", err)
+	}
+	var got []string
+	for _, suite := range suites.Suites {
+		for _, tc := range suite.TestCases {
+			got = append(got, fmt.Sprintf("%s.%s:%s", suite.Name, tc.Name, tc.GetTestStatus()))
+			if tc.SystemOut != nil || tc.SystemErr != nil {
+				t.Errorf("test case %s kept its output, want it dropped", tc.Name)
+			}
+		}
+	}
+	if want := []string{"e2e.TestA:failed", "e2e.TestC:passed", "unit.TestB:passed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readJunit() = %v, want %v", got, want)
+	}
+}
+
+func TestNewResultSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		jc      config.JobConfig
+		wantErr bool
+	}{{
+		name: "local",
+		jc:   config.JobConfig{Source: config.LocalSource, Path: "testdata/local"},
+	}, {
+		name:    "prow mirror without the job",
+		jc:      config.JobConfig{Name: "missing", Type: prow.PeriodicJob, Path: "testdata/local"},
+		wantErr: true,
+	}, {
+		name:    "local without path",
+		jc:      config.JobConfig{Source: config.LocalSource},
+		wantErr: true,
+	}, {
+		name:    "github-actions without workflow",
+		jc:      config.JobConfig{Source: config.GithubActionsSource},
+		wantErr: true,
+	}, {
+		name:    "unknown",
+		jc:      config.JobConfig{Source: "jenkins"},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newResultSource(tt.jc, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newResultSource() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"knative.dev/test-infra/pkg/junit"
@@ -75,3 +76,119 @@ func Test_filterOutParentTests(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterOutParentTests_GoTestJSON(t *testing.T) {
+	events := `{"Action":"run","Package":"knative.dev/serving/test/e2e","Test":"TestRoute"}
+{"Action":"run","Package":"knative.dev/serving/test/e2e","Test":"TestRoute/http"}
+{"Action":"pass","Package":"knative.dev/serving/test/e2e","Test":"TestRoute/http","Elapsed":1}
+{"Action":"run","Package":"knative.dev/serving/test/e2e","Test":"TestRoute/grpc"}
+{"Action":"output","Package":"knative.dev/serving/test/e2e","Test":"TestRoute/grpc","Output":"    route_test.go:10: timeout\n"}
+{"Action":"fail","Package":"knative.dev/serving/test/e2e","Test":"TestRoute/grpc","Elapsed":2}
+{"Action":"fail","Package":"knative.dev/serving/test/e2e","Test":"TestRoute","Elapsed":3}
+{"Action":"fail","Package":"knative.dev/serving/test/e2e","Elapsed":3}
+`
+	testSuites, err := junit.FromGoTestJSON(strings.NewReader(events))
+	if err != nil {
+		t.Fatalf("FromGoTestJSON() = %v", err)
+	}
+	var got []string
+	for _, tc := range filterOutParentTests(testSuites.Suites[0].TestCases) {
+		got = append(got, tc.Name+"="+string(tc.GetTestStatus()))
+	}
+	if want := []string{"TestRoute/http=passed", "TestRoute/grpc=failed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterOutParentTests() = %v, want %v", got, want)
+	}
+}
+
+func TestAddSuiteToRepoData_Retries(t *testing.T) {
+	failure := junit.NewResult("boom")
+	passed := junit.TestCase{Name: "TestRetried"}
+	failed := junit.TestCase{Name: "TestRetried", Failure: failure}
+	skipped := junit.TestCase{Name: "TestRetried", Skipped: failure}
+	errored := junit.TestCase{Name: "TestRetried", Error: failure}
+	tests := []struct {
+		name         string
+		attempts     []junit.TestCase
+		wantStatus   junit.TestStatusEnum
+		flakyInBuild bool
+	}{{
+		name:       "passed",
+		attempts:   []junit.TestCase{passed},
+		wantStatus: junit.Passed,
+	}, {
+		name:         "passed on retry",
+		attempts:     []junit.TestCase{failed, passed},
+		wantStatus:   junit.Passed,
+		flakyInBuild: true,
+	}, {
+		name:         "passed on second retry",
+		attempts:     []junit.TestCase{failed, failed, passed},
+		wantStatus:   junit.Passed,
+		flakyInBuild: true,
+	}, {
+		name:         "passed before failed attempt",
+		attempts:     []junit.TestCase{passed, failed},
+		wantStatus:   junit.Passed,
+		flakyInBuild: true,
+	}, {
+		name:       "failed all retries",
+		attempts:   []junit.TestCase{failed, failed, failed},
+		wantStatus: junit.Failed,
+	}, {
+		name:       "skipped then failed",
+		attempts:   []junit.TestCase{skipped, failed},
+		wantStatus: junit.Failed,
+	}, {
+		name:         "passed after error",
+		attempts:     []junit.TestCase{errored, passed},
+		wantStatus:   junit.Passed,
+		flakyInBuild: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := &RepoData{}
+			suite := &junit.TestSuite{Name: "e2e", TestCases: tt.attempts}
+			addSuiteToRepoData(suite, 1, rd)
+			ts := rd.TestStats["e2e.TestRetried"]
+			if got := len(ts.Passed) + len(ts.Failed) + len(ts.Skipped); got != 1 {
+				t.Errorf("TestStat = %+v, want 1 result", ts)
+			}
+			if got := ts.getResult(1); got != tt.wantStatus {
+				t.Errorf("getResult() = %q, want %q", got, tt.wantStatus)
+			}
+			if got := ts.isFlakyInBuild(); got != tt.flakyInBuild {
+				t.Errorf("isFlakyInBuild() = %t, want %t", got, tt.flakyInBuild)
+			}
+			if tt.flakyInBuild && ts.FailureSignatures[1] != failure.Data {
+				t.Errorf("FailureSignatures = %v, want the failure of the retried attempt", ts.FailureSignatures)
+			}
+		})
+	}
+}
+
+// setBuildsCount sets the count of builds scanned, and restores it when the
+// test is done
+func setBuildsCount(t *testing.T, count int) {
+	t.Helper()
+	oldBuildsCount, oldRequiredCount := buildsCount, requiredCount
+	t.Cleanup(func() {
+		buildsCount, requiredCount = oldBuildsCount, oldRequiredCount
+	})
+	buildsCount = count
+	requiredCount = requiredRatio * float32(buildsCount)
+}
+
+func TestFlakyInBuildStatus(t *testing.T) {
+	setBuildsCount(t, 2)
+	ts := &TestStat{TestName: "e2e.TestRetried", Passed: []int{1, 2}, FlakyInBuild: []int{2}}
+	if ts.isPassed() {
+		t.Error("isPassed() = true, want false for a test passing only on retry")
+	}
+	if got := ts.getTestStatus(); got != flakyInBuildStatus {
+		t.Errorf("getTestStatus() = %q, want %q", got, flakyInBuildStatus)
+	}
+	rd := RepoData{TestStats: map[string]*TestStat{ts.TestName: ts}}
+	if got := createFlakyInBuildMessage(rd); got != "\n1 tests passed only on retry in some builds:\n>- e2e.TestRetried (1 builds)" {
+		t.Errorf("createFlakyInBuildMessage() = %q", got)
+	}
+}
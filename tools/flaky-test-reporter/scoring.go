@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// scoring.go scores the flakiness of tests from their results across builds
+
+package main
+
+import (
+	"math"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+const (
+	flakyClass     = "flaky"
+	failingClass   = "consistently-failing"
+	recoveredClass = "recovered"
+)
+
+// FlakinessScore is the flakiness of a test. Score is the weighted failure
+// rate of the test, recent results weigh more and failures clustered with
+// many other failures in the same build weigh less. Lower and Upper are the
+// bounds of the confidence interval of the failure rate.
+type FlakinessScore struct {
+	Score          float64
+	Lower          float64
+	Upper          float64
+	Classification string // flaky, consistently-failing, recovered or empty
+}
+
+// scoreTests scores the flakiness of all tests of RepoData, based on the
+// flakiness config of the job
+func (rd *RepoData) scoreTests() {
+	fc := rd.Config.Flakiness.WithDefaults()
+	clustered := rd.getClusteredBuilds(fc)
+	for _, ts := range rd.TestStats {
+		ts.Score = scoreTest(ts, rd.BuildIDs, clustered, fc)
+	}
+}
+
+// getClusteredBuilds returns the builds where the ratio of failed tests is
+// above the cluster ratio
+func (rd *RepoData) getClusteredBuilds(fc config.FlakinessConfig) sets.Int {
+	ran, failed := rd.countResultsPerBuild()
+	clustered := sets.NewInt()
+	for buildID, count := range failed {
+		if count >= fc.ClusterMinFailures && float64(count)/float64(ran[buildID]) > fc.ClusterRatio {
+			clustered.Insert(buildID)
+		}
+	}
+	return clustered
+}
+
+// countResultsPerBuild returns the count of tests passed or failed, and the
+// count of tests failed, in each build
+func (rd *RepoData) countResultsPerBuild() (map[int]int, map[int]int) {
+	ran := make(map[int]int)
+	failed := make(map[int]int)
+	for _, ts := range rd.TestStats {
+		for _, buildID := range ts.Passed {
+			ran[buildID]++
+		}
+		for _, buildID := range ts.Failed {
+			ran[buildID]++
+			failed[buildID]++
+		}
+	}
+	return ran, failed
+}
+
+// scoreTest scores a test from its results in buildIDs, sorted from the
+// latest build
+func scoreTest(ts *TestStat, buildIDs []int, clustered sets.Int, fc config.FlakinessConfig) *FlakinessScore {
+	passed := sets.NewInt(ts.Passed...)
+	failed := sets.NewInt(ts.Failed...)
+
+	var sumW, sumW2, failW float64
+	passStreak, streakDone := 0, false
+	for i, buildID := range buildIDs {
+		if !failed.Has(buildID) && !passed.Has(buildID) {
+			continue
+		}
+		w := math.Pow(0.5, float64(i)/fc.HalfLife)
+		if failed.Has(buildID) {
+			streakDone = true
+			if clustered.Has(buildID) {
+				w *= fc.ClusterWeight
+			}
+			failW += w
+		} else if !streakDone {
+			passStreak++
+		}
+		sumW += w
+		sumW2 += w * w
+	}
+
+	score := &FlakinessScore{Upper: 1}
+	if sumW == 0 {
+		return score
+	}
+	score.Score = failW / sumW
+	// Kish's effective sample size of the weighted results
+	n := sumW * sumW / sumW2
+	z := math.Sqrt2 * math.Erfinv(fc.Confidence)
+	score.Lower, score.Upper = wilsonInterval(score.Score, n, z)
+
+	switch {
+	case failed.Len() == 0:
+	case passStreak >= fc.RecoveredBuilds:
+		score.Classification = recoveredClass
+	case score.Lower >= fc.FailingLowerBound:
+		score.Classification = failingClass
+	case passed.Len() > 0 && score.Lower >= fc.FlakyLowerBound:
+		score.Classification = flakyClass
+	}
+	return score
+}
+
+// wilsonInterval returns the Wilson score interval of a rate p observed over
+// n samples, for the z-score of the confidence level
+func wilsonInterval(p, n, z float64) (float64, float64) {
+	z2 := z * z
+	denom := 1 + z2/n
+	center := p + z2/(2*n)
+	margin := z * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return math.Max(0, (center-margin)/denom), math.Min(1, (center+margin)/denom)
+}
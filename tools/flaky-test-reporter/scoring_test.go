@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/test-infra/tools/flaky-test-reporter/config"
+)
+
+func TestScoreTests(t *testing.T) {
+	// Builds are sorted from the latest
+	buildIDs := []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	tests := []struct {
+		name      string
+		ts        TestStat
+		fc        config.FlakinessConfig
+		outage    bool // many other tests failed in build 9
+		wantClass string
+		wantFlaky bool
+	}{{
+		name: "always passed",
+		ts:   TestStat{Passed: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+	}, {
+		name:      "failed once",
+		ts:        TestStat{Passed: []int{10, 8, 7, 6, 5, 4, 3, 2, 1}, Failed: []int{9}},
+		wantClass: flakyClass,
+		wantFlaky: true,
+	}, {
+		name:   "failed once in an outage",
+		ts:     TestStat{Passed: []int{10, 8, 7, 6, 5, 4, 3, 2, 1}, Failed: []int{9}},
+		outage: true,
+	}, {
+		name:      "failed a few times",
+		ts:        TestStat{Passed: []int{10, 8, 6, 5, 3, 2, 1}, Failed: []int{9, 7, 4}},
+		wantClass: flakyClass,
+		wantFlaky: true,
+	}, {
+		name:      "always failed",
+		ts:        TestStat{Failed: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		wantClass: failingClass,
+	}, {
+		name:      "mostly failed",
+		ts:        TestStat{Passed: []int{6}, Failed: []int{10, 9, 8, 7, 5, 4, 3, 2, 1}},
+		wantClass: failingClass,
+	}, {
+		name:      "failed before the latest passes",
+		ts:        TestStat{Passed: []int{10, 9, 8, 7, 5, 3, 2, 1}, Failed: []int{6, 4}},
+		wantClass: recoveredClass,
+	}, {
+		name:      "not recovered with more latest passes required",
+		ts:        TestStat{Passed: []int{10, 9, 8, 7, 5, 3, 2, 1}, Failed: []int{6, 4}},
+		fc:        config.FlakinessConfig{RecoveredBuilds: 5},
+		wantClass: flakyClass,
+		wantFlaky: true,
+	}, {
+		name: "failed once, with a stricter lower bound",
+		ts:   TestStat{Passed: []int{10, 8, 7, 6, 5, 4, 3, 2, 1}, Failed: []int{9}},
+		fc:   config.FlakinessConfig{FlakyLowerBound: 0.05},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := tt.ts
+			ts.TestName = "suite.test"
+			rd := RepoData{
+				Config:    config.JobConfig{Flakiness: tt.fc},
+				BuildIDs:  buildIDs,
+				TestStats: map[string]*TestStat{ts.TestName: &ts},
+			}
+			if tt.outage {
+				for i := 0; i < 5; i++ {
+					name := fmt.Sprintf("suite.outage%d", i)
+					rd.TestStats[name] = &TestStat{TestName: name, Passed: []int{10, 8, 7, 6, 5, 4, 3, 2, 1}, Failed: []int{9}}
+				}
+			}
+			rd.scoreTests()
+
+			if ts.Score == nil {
+				t.Fatal("scoreTests() did not score the test")
+			}
+			if got := ts.Score.Classification; got != tt.wantClass {
+				t.Errorf("Classification = %q, want %q (score %+v)", got, tt.wantClass, *ts.Score)
+			}
+			if got := ts.isFlaky(); got != tt.wantFlaky {
+				t.Errorf("isFlaky() = %t, want %t", got, tt.wantFlaky)
+			}
+			if ts.Score.Lower > ts.Score.Score || ts.Score.Score > ts.Score.Upper {
+				t.Errorf("Score %v is not within [%v, %v]", ts.Score.Score, ts.Score.Lower, ts.Score.Upper)
+			}
+		})
+	}
+}
+
+func TestGetClusteredBuilds(t *testing.T) {
+	rd := RepoData{
+		TestStats: map[string]*TestStat{
+			"a": {Passed: []int{3}, Failed: []int{1, 2}},
+			"b": {Passed: []int{2, 3}, Failed: []int{1}},
+			"c": {Passed: []int{2, 3}, Failed: []int{1}},
+			"d": {Passed: []int{1, 2, 3}},
+		},
+	}
+	fc := config.FlakinessConfig{}.WithDefaults()
+	got := rd.getClusteredBuilds(fc)
+	if !got.Equal(sets.NewInt(1)) {
+		t.Errorf("getClusteredBuilds() = %v, want [1]", got.List())
+	}
+	fc.ClusterMinFailures = 4
+	if got := rd.getClusteredBuilds(fc); got.Len() != 0 {
+		t.Errorf("getClusteredBuilds() with 4 min failures = %v, want none", got.List())
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	lower, upper := wilsonInterval(0.2, 10, 1.96)
+	// Reference values for 2 failures out of 10 runs at 95% confidence.
+	if math.Abs(lower-0.0567) > 0.001 || math.Abs(upper-0.5098) > 0.001 {
+		t.Errorf("wilsonInterval() = [%v, %v], want [0.0567, 0.5098]", lower, upper)
+	}
+}
+
+func TestGetTestStatus_Scored(t *testing.T) {
+	tests := map[string]string{
+		flakyClass:     flakyStatus,
+		recoveredClass: recoveredStatus,
+		failingClass:   failedStatus,
+	}
+	for class, want := range tests {
+		ts := TestStat{Passed: []int{1}, Failed: []int{2}, Score: &FlakinessScore{Classification: class}}
+		if got := ts.getTestStatus(); got != want {
+			t.Errorf("getTestStatus() for %q = %q, want %q", class, got, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// signature.go normalizes junit failure messages into signatures, so that
+// failures of a test sharing the same cause can be grouped
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxSignatureLength is the max count of characters of a signature, long
+	// failure messages usually differ only by their output
+	maxSignatureLength = 200
+	// noMessageSignature is shown for failures without a message
+	noMessageSignature = "no failure message"
+)
+
+// signatureReplacements are applied in order to failure messages, to replace
+// the parts varying between builds by placeholders
+var signatureReplacements = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Timestamps, i.e. 2020-01-02T15:04:05.999Z or 2020/01/02 15:04:05
+	{regexp.MustCompile(`\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(\.\d+)?\b`), "<time>"},
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}(:\d+)?\b`), "<ip>"},
+	// Pointers and hashes, i.e. 0xc000123456 or image digests
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`), "<hex>"},
+	{regexp.MustCompile(`\b[0-9a-f]{12,}\b`), "<hex>"},
+	{regexp.MustCompile(`\b\d+(\.\d+)?(ns|us|µs|ms|s|m|h)\b`), "<duration>"},
+	// Pods of deployments, i.e. activator-7b8d5f9c6d-x2vqk
+	{regexp.MustCompile(`-[a-z0-9]{8,10}-[a-z0-9]{5}\b`), "-<pod>"},
+	// Suffixes of helpers.AppendRandomString, i.e. test-namespace-xkqzjwpa.
+	// This also replaces 8 letters words ending a name, which is fine as long
+	// as it's done the same way for all messages.
+	{regexp.MustCompile(`([a-z0-9])-[a-z]{8}\b`), "$1-<rand>"},
+	{regexp.MustCompile("`"), "'"},
+	{regexp.MustCompile(`\s+`), " "},
+}
+
+// failureSignature is a group of failures of a test sharing the same
+// normalized failure message
+type failureSignature struct {
+	Signature string
+	Count     int
+	Example   int // the latest build ID failed with this signature
+}
+
+// normalizeFailure turns a failure message into a signature, by replacing
+// the parts varying between builds, i.e. timestamps, pod names and random
+// suffixes
+func normalizeFailure(message string) string {
+	for _, r := range signatureReplacements {
+		message = r.re.ReplaceAllString(message, r.repl)
+	}
+	message = strings.TrimSpace(message)
+	if runes := []rune(message); len(runes) > maxSignatureLength {
+		message = string(runes[:maxSignatureLength]) + "..."
+	}
+	return message
+}
+
+// getFailureSignatures groups the failures of a test by signature, the most
+// frequent first
+func (ts *TestStat) getFailureSignatures() []failureSignature {
+	var signatures []failureSignature
+	indexes := make(map[string]int)
+	// Failed is sorted from the latest build, so the first build found for
+	// each signature is the latest
+	for _, buildID := range ts.Failed {
+		signature := ts.FailureSignatures[buildID]
+		if signature == "" {
+			signature = noMessageSignature
+		}
+		if i, ok := indexes[signature]; ok {
+			signatures[i].Count++
+			continue
+		}
+		indexes[signature] = len(signatures)
+		signatures = append(signatures, failureSignature{Signature: signature, Count: 1, Example: buildID})
+	}
+	sort.SliceStable(signatures, func(i, j int) bool {
+		return signatures[i].Count > signatures[j].Count
+	})
+	return signatures
+}
+
+// createFailureModesContent lists the distinct failure modes of a test with
+// their counts and an example build, for the Github issue comment
+func createFailureModesContent(rd RepoData, ts *TestStat) string {
+	signatures := ts.getFailureSignatures()
+	if len(signatures) == 0 {
+		return ""
+	}
+	content := fmt.Sprintf("\nFailure modes (%d):", len(signatures))
+	for _, s := range signatures {
+		content += fmt.Sprintf("\n* %d times: `%s` ([example](%s))", s.Count, s.Signature, rd.getBuildURL(s.Example))
+	}
+	return content
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"knative.dev/test-infra/pkg/helpers"
+)
+
+func TestNormalizeFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{{
+		name:    "timestamp",
+		message: "2020-01-02T15:04:05.123Z: timed out waiting for readiness",
+		want:    "<time>: timed out waiting for readiness",
+	}, {
+		name:    "log time and duration",
+		message: "15:04:05 service not ready after 30.5s",
+		want:    "<time> service not ready after <duration>",
+	}, {
+		name:    "pod name",
+		message: "pod activator-7b8d5f9c6d-x2vqk is not running",
+		want:    "pod activator-<pod> is not running",
+	}, {
+		name:    "random suffix",
+		message: "namespace " + helpers.AppendRandomString("serving-tests") + " not found",
+		want:    "namespace serving-tests-<rand> not found",
+	}, {
+		name:    "uuid, ip and digest",
+		message: "request 123e4567-e89b-12d3-a456-426614174000 to 10.0.0.12:8080 for sha256:0123456789abcdef0123",
+		want:    "request <uuid> to <ip> for sha256:<hex>",
+	}, {
+		name:    "multiline output",
+		message: "\n  e2e_test.go:42: `foo` failed\n\tdetails\n",
+		want:    "e2e_test.go:42: 'foo' failed details",
+	}, {
+		name:    "long output",
+		message: strings.Repeat("a ", maxSignatureLength),
+		want:    strings.Repeat("a ", maxSignatureLength/2) + "...",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeFailure(tt.message); got != tt.want {
+				t.Errorf("normalizeFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFailureSignatures(t *testing.T) {
+	ts := &TestStat{
+		Passed: []int{5},
+		Failed: []int{4, 3, 2, 1},
+		FailureSignatures: map[int]string{
+			4: "timed out",
+			3: "connection refused",
+			2: "connection refused",
+		},
+	}
+	want := []failureSignature{
+		{Signature: "connection refused", Count: 2, Example: 3},
+		{Signature: "timed out", Count: 1, Example: 4},
+		{Signature: noMessageSignature, Count: 1, Example: 1},
+	}
+	if got := ts.getFailureSignatures(); !reflect.DeepEqual(got, want) {
+		t.Errorf("getFailureSignatures() = %v, want %v", got, want)
+	}
+
+	rd := RepoData{BuildURLs: map[int]string{3: "https://ci/3"}}
+	content := createFailureModesContent(rd, ts)
+	if !strings.Contains(content, "Failure modes (3):") ||
+		!strings.Contains(content, "* 2 times: `connection refused` ([example](https://ci/3))") {
+		t.Errorf("createFailureModesContent() = %q, want failure modes with counts and examples", content)
+	}
+	if got := createFailureModesContent(rd, &TestStat{Passed: []int{1}}); got != "" {
+		t.Errorf("createFailureModesContent() without failure = %q, want empty", got)
+	}
+}
@@ -56,13 +56,15 @@ func createSlackMessageForRepo(rd RepoData, flakyIssuesMap map[string][]flakyIss
 	} else {
 		for _, testFullName := range flakyTests {
 			message += fmt.Sprintf("\n>- %s", testFullName)
-			if flakyIssues, ok := flakyIssuesMap[getIdentityForTest(testFullName, rd.Config.Repo)]; ok && rd.Config.IssueRepo != "" {
+			if flakyIssues, ok := flakyIssuesMap[getIdentityForTest(testFullName, rd.Config.Repo, rd.Config.Name)]; ok && rd.Config.IssueRepo != "" {
 				for _, fi := range flakyIssues {
 					message += fmt.Sprintf("\t%s", fi.issue.GetHTMLURL())
 				}
 			}
 		}
 	}
+	message += createFlakyInBuildMessage(rd)
+	message += createOutageMessage(rd)
 
 	if testgridTabURL, err := testgrid.GetTestgridTabURL(rd.Config.Name, []string{testgridFilter}); err != nil {
 		log.Println(err) // don't fail as this could be optional
@@ -72,7 +74,26 @@ func createSlackMessageForRepo(rd RepoData, flakyIssuesMap map[string][]flakyIss
 	return message
 }
 
-func sendSlackNotifications(repoDataAll []RepoData, c slackutil.WriteOperations, flakyIssues map[string][]flakyIssue, dryrun bool) error {
+// createFlakyInBuildMessage lists the tests that passed only on retry, these
+// are flaky even if they didn't fail any build
+func createFlakyInBuildMessage(rd RepoData) string {
+	tests := getFlakyInBuildTests(rd)
+	if len(tests) == 0 {
+		return ""
+	}
+	message := fmt.Sprintf("\n%d tests passed only on retry in some builds:", len(tests))
+	if len(tests) > countThreshold {
+		return message + fmt.Sprintf("\n>- skip displaying all tests as there are more than %d", countThreshold)
+	}
+	for _, testFullName := range tests {
+		message += fmt.Sprintf("\n>- %s (%d builds)", testFullName, len(rd.TestStats[testFullName].FlakyInBuild))
+	}
+	return message
+}
+
+// sendSlackNotifications posts the message created by createMessage for each
+// job in its Slack channels
+func sendSlackNotifications(repoDataAll []RepoData, c slackutil.WriteOperations, createMessage func(RepoData) (string, error), dryrun bool) error {
 	var allErrs []error
 	for _, rd := range repoDataAll {
 		channels := rd.Config.SlackChannels
@@ -80,6 +101,12 @@ func sendSlackNotifications(repoDataAll []RepoData, c slackutil.WriteOperations,
 			log.Printf("cannot find Slack channel for job '%s' in repo '%s', skipping Slack notification", rd.Config.Name, rd.Config.Repo)
 			continue
 		}
+		message, err := createMessage(rd)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			log.Printf("failed creating Slack message for job '%s' in repo '%s': '%v'", rd.Config.Name, rd.Config.Repo, err)
+			continue
+		}
 		ch := make(chan bool, len(channels))
 		wg := sync.WaitGroup{}
 		for i := range channels {
@@ -87,7 +114,6 @@ func sendSlackNotifications(repoDataAll []RepoData, c slackutil.WriteOperations,
 			channel := channels[i]
 			go func() {
 				defer wg.Done()
-				message := createSlackMessageForRepo(rd, flakyIssues)
 				if err := helpers.Run(
 					fmt.Sprintf("post Slack message for job '%s' from repo '%s' in channel '%s'", rd.Config.Name, rd.Config.Repo, channel.Name),
 					func() error {
@@ -17,8 +17,11 @@ limitations under the License.
 package junit
 
 import (
+	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
+	"regexp"
 
 	"github.com/spf13/cobra"
 
@@ -51,7 +54,7 @@ func AddCommands(topLevel *cobra.Command) {
 			tc := junit.TestCase{Name: opt.name}
 			if opt.errMsg != "" {
 				// errMsg := html.EscapeString(errMsg)
-				tc.Failure = &opt.errMsg
+				tc.Failure = junit.NewResult(opt.errMsg)
 			}
 			suite.AddTestCase(tc)
 			// Ignore the error as it only happens if the test suite name already exists.
@@ -67,5 +70,154 @@ func AddCommands(topLevel *cobra.Command) {
 	}
 
 	addOptions(junitCmd, opt)
+	addMergeCommand(junitCmd)
+	addFilterCommand(junitCmd)
+	addDiffCommand(junitCmd)
+	addFromGoTestCommand(junitCmd)
 	topLevel.AddCommand(junitCmd)
 }
+
+// readFiles reads and merges the junit files
+func readFiles(paths []string) *junit.TestSuites {
+	var sets []*junit.TestSuites
+	for _, p := range paths {
+		testSuites, err := junit.ReadFile(p)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sets = append(sets, testSuites)
+	}
+	return junit.Merge(sets...)
+}
+
+func writeFile(dest string, testSuites *junit.TestSuites) {
+	contents, err := testSuites.ToBytes("", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(dest, contents, 0644); err != nil {
+		log.Fatalf("Error writing to file %q: %v", dest, err)
+	}
+}
+
+func addMergeCommand(junitCmd *cobra.Command) {
+	var dest string
+	var mergeCmd = &cobra.Command{
+		Use:   "merge FILE...",
+		Short: "Merge junit files into one, the results of later files replace the results of the same tests in earlier files.",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			writeFile(dest, readFiles(args))
+		},
+	}
+	mergeCmd.Flags().StringVar(&dest, "dest", "junit_result.xml", "Where junit xml writes to")
+	junitCmd.AddCommand(mergeCmd)
+}
+
+func addFilterCommand(junitCmd *cobra.Command) {
+	var (
+		dest     string
+		statuses []string
+		name     string
+		suite    string
+	)
+	var filterCmd = &cobra.Command{
+		Use:   "filter FILE...",
+		Short: "Filter the tests of junit files by status and name, files are merged first.",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var filters []junit.TestCaseFilter
+			if len(statuses) > 0 {
+				var enums []junit.TestStatusEnum
+				for _, s := range statuses {
+					switch status := junit.TestStatusEnum(s); status {
+					case junit.Passed, junit.Failed, junit.Skipped:
+						enums = append(enums, status)
+					default:
+						log.Fatalf("Invalid status %q, must be one of passed, failed or skipped", s)
+					}
+				}
+				filters = append(filters, junit.StatusFilter(enums...))
+			}
+			if name != "" {
+				re, err := regexp.Compile(name)
+				if err != nil {
+					log.Fatalf("Invalid name regex %q: %v", name, err)
+				}
+				filters = append(filters, junit.NameFilter(re))
+			}
+			if suite != "" {
+				re, err := regexp.Compile(suite)
+				if err != nil {
+					log.Fatalf("Invalid suite regex %q: %v", suite, err)
+				}
+				filters = append(filters, junit.SuiteFilter(re))
+			}
+			writeFile(dest, junit.Filter(readFiles(args), filters...))
+		},
+	}
+	pf := filterCmd.Flags()
+	pf.StringVar(&dest, "dest", "junit_result.xml", "Where junit xml writes to")
+	pf.StringSliceVar(&statuses, "status", nil, "Statuses of tests to keep, any of passed, failed and skipped")
+	pf.StringVar(&name, "name", "", "Regex matching the names of tests to keep")
+	pf.StringVar(&suite, "suite", "", "Regex matching the names of suites to keep")
+	junitCmd.AddCommand(filterCmd)
+}
+
+func addDiffCommand(junitCmd *cobra.Command) {
+	var failOnNewFailures bool
+	var diffCmd = &cobra.Command{
+		Use:   "diff BASE_FILE HEAD_FILE",
+		Short: "List the new failures, fixed tests and newly skipped tests of the head junit file compared with the base one.",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			d := junit.Diff(readFiles(args[:1]), readFiles(args[1:]))
+			out := cmd.OutOrStdout()
+			for _, section := range []struct {
+				title string
+				ids   []junit.TestID
+			}{
+				{"New failures", d.NewFailures},
+				{"Fixed tests", d.Fixed},
+				{"Newly skipped tests", d.NewlySkipped},
+			} {
+				fmt.Fprintf(out, "%s (%d):\n", section.title, len(section.ids))
+				for _, id := range section.ids {
+					fmt.Fprintf(out, "  %s\n", id)
+				}
+			}
+			if failOnNewFailures && len(d.NewFailures) > 0 {
+				log.Fatalf("%d new failures", len(d.NewFailures))
+			}
+		},
+	}
+	diffCmd.Flags().BoolVar(&failOnNewFailures, "fail-on-new-failures", false, "Exit with an error if there are new failures")
+	junitCmd.AddCommand(diffCmd)
+}
+
+func addFromGoTestCommand(junitCmd *cobra.Command) {
+	var dest string
+	var fromGoTestCmd = &cobra.Command{
+		Use:   "from-gotest [FILE]",
+		Short: "Convert the output of `go test -json` read from FILE, or from stdin, into junit.",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			in := cmd.InOrStdin()
+			if len(args) == 1 {
+				f, err := os.Open(args[0])
+				if err != nil {
+					log.Fatal(err)
+				}
+				defer f.Close()
+				in = f
+			}
+			testSuites, err := junit.FromGoTestJSON(in)
+			if err != nil {
+				log.Fatal(err)
+			}
+			writeFile(dest, testSuites)
+		},
+	}
+	fromGoTestCmd.Flags().StringVar(&dest, "dest", "junit_result.xml", "Where junit xml writes to")
+	junitCmd.AddCommand(fromGoTestCmd)
+}